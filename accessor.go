@@ -0,0 +1,50 @@
+package goconfig
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// setAccessorVal parses raw into the value type of the setter registered with
+// WithFieldAccessor for the field's type, then calls the setter with a pointer
+// to the field.
+func (c *Loader) setAccessorVal(fval reflect.Value, raw string, set reflect.Value) error {
+	t := fval.Type()
+
+	if !isAccessorFunc(set, t) {
+		return errors.Errorf("field accessor for %s must be a func(*%s, V) or func(*%s, V) error", t, t, t)
+	}
+
+	if !fval.CanAddr() {
+		return errors.Errorf("field accessor for %s needs an addressable value", t)
+	}
+
+	v := reflect.New(set.Type().In(1)).Elem()
+	if _, err := c.setFieldVal(v, raw, ""); err != nil {
+		return err
+	}
+
+	out := set.Call([]reflect.Value{fval.Addr(), v})
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+
+	return nil
+}
+
+// isAccessorFunc reports whether set is a func(*T, V) or func(*T, V) error for T t.
+func isAccessorFunc(set reflect.Value, t reflect.Type) bool {
+	if !set.IsValid() || set.Kind() != reflect.Func || set.IsNil() {
+		return false
+	}
+
+	st := set.Type()
+	if st.NumIn() != 2 || st.In(0) != reflect.PointerTo(t) {
+		return false
+	}
+
+	return st.NumOut() == 0 || (st.NumOut() == 1 && st.Out(0) == errorType)
+}
@@ -0,0 +1,100 @@
+package goconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// portNumber only exposes its value through methods.
+type portNumber struct {
+	n uint16
+}
+
+func (p *portNumber) Set(n uint16) error {
+	if n == 0 {
+		return errors.New("port must not be zero")
+	}
+
+	p.n = n
+
+	return nil
+}
+
+func (p portNumber) Get() uint16 {
+	return p.n
+}
+
+// upperName has both a setter and UnmarshalText.
+type upperName struct {
+	v string
+}
+
+func (u *upperName) SetName(v string) {
+	u.v = strings.ToUpper(v)
+}
+
+func (u *upperName) UnmarshalText(text []byte) error {
+	u.v = "text:" + string(text)
+	return nil
+}
+
+func TestFieldAccessor(t *testing.T) {
+	type Config struct {
+		Port    portNumber
+		Admin   *portNumber
+		Ports   []portNumber
+		Name    upperName
+		Aliases map[string]upperName `format:"kv"`
+	}
+
+	loader := New(
+		WithPrefix("ACC"),
+		WithFieldAccessor(reflect.TypeOf(portNumber{}), func(p *portNumber, v uint16) error { return p.Set(v) }),
+		WithFieldAccessor(reflect.TypeOf(upperName{}), (*upperName).SetName),
+	)
+
+	t.Setenv("ACC_PORT", "8080")
+	t.Setenv("ACC_ADMIN", "9090")
+	t.Setenv("ACC_PORTS", "80,443")
+	t.Setenv("ACC_NAME", "api")
+	t.Setenv("ACC_ALIASES", "a=web")
+
+	var cfg Config
+	require.NoError(t, loader.Load(&cfg))
+
+	assert.Equal(t, uint16(8080), cfg.Port.Get())
+	assert.Equal(t, uint16(9090), cfg.Admin.Get())
+	assert.Equal(t, []portNumber{{n: 80}, {n: 443}}, cfg.Ports)
+	assert.Equal(t, "API", cfg.Name.v, "the setter takes precedence over UnmarshalText")
+	assert.Equal(t, map[string]upperName{"a": {v: "WEB"}}, cfg.Aliases)
+
+	t.Run("without accessor", func(t *testing.T) {
+		var cfg Config
+		require.NoError(t, New(WithPrefix("ACC")).Load(&cfg))
+		assert.Equal(t, "text:api", cfg.Name.v)
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		t.Setenv("ACC_PORT", "70000")
+		assert.ErrorContains(t, loader.Load(&Config{}), "cannot set field ACC_PORT value: value 70000 overflows uint16")
+	})
+
+	t.Run("setter error", func(t *testing.T) {
+		t.Setenv("ACC_PORT", "0")
+		assert.ErrorContains(t, loader.Load(&Config{}), "cannot set field ACC_PORT value: port must not be zero")
+	})
+
+	t.Run("invalid setter", func(t *testing.T) {
+		err := New(
+			WithPrefix("ACC"),
+			WithFieldAccessor(reflect.TypeOf(portNumber{}), func(p portNumber, v uint16) {}),
+		).Load(&Config{})
+
+		assert.ErrorContains(t, err, "field accessor for goconfig.portNumber must be a func(*goconfig.portNumber, V)")
+	})
+}
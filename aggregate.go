@@ -0,0 +1,33 @@
+package goconfig
+
+import (
+	stderrors "errors"
+
+	"github.com/pkg/errors"
+)
+
+// ErrLoad is matched by the error Load returns when WithErrorAggregation
+// collected one or more errors.
+var ErrLoad = errors.New("cannot load configuration")
+
+// collectErr records err for WithErrorAggregation and reports whether it was
+// collected, in which case loading goes on.
+func (c *Loader) collectErr(err error) bool {
+	if !c.aggregateErrors {
+		return false
+	}
+
+	c.state.errs = append(c.state.errs, err)
+
+	return true
+}
+
+// loadErr returns ErrLoad joined with the collected errors, one per line,
+// or nil when none was collected.
+func (c *Loader) loadErr() error {
+	if len(c.state.errs) == 0 {
+		return nil
+	}
+
+	return stderrors.Join(append([]error{ErrLoad}, c.state.errs...)...)
+}
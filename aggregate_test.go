@@ -0,0 +1,95 @@
+package goconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorAggregation(t *testing.T) {
+	type DB struct {
+		Port int
+		Host string `required:"true"`
+	}
+
+	type Config struct {
+		Port    int
+		Timeout float64
+		Name    string
+		DB      DB
+	}
+
+	t.Setenv("AGG_PORT", "abc")
+	t.Setenv("AGG_TIMEOUT", "slow")
+	t.Setenv("AGG_NAME", "app")
+	t.Setenv("AGG_DB_PORT", "-")
+
+	t.Run("collects every field", func(t *testing.T) {
+		var cfg Config
+
+		err := New(WithPrefix("AGG"), WithErrorAggregation()).Load(&cfg)
+		require.Error(t, err)
+
+		assert.ErrorIs(t, err, ErrLoad)
+		assert.ErrorIs(t, err, ErrMissingRequired)
+
+		lines := strings.Split(err.Error(), "\n")
+		require.Len(t, lines, 5)
+		assert.Equal(t, "cannot load configuration", lines[0])
+		assert.Contains(t, lines[1], "AGG_PORT")
+		assert.Contains(t, lines[2], "AGG_TIMEOUT")
+		assert.Contains(t, lines[3], "AGG_DB_PORT")
+		assert.Equal(t, "AGG_DB_HOST: required variable is not set", lines[4])
+
+		// the fields without errors are still loaded
+		assert.Equal(t, "app", cfg.Name)
+	})
+
+	t.Run("stops at the first error by default", func(t *testing.T) {
+		err := New(WithPrefix("AGG")).Load(&Config{})
+		require.Error(t, err)
+
+		assert.NotErrorIs(t, err, ErrLoad)
+		assert.Contains(t, err.Error(), "AGG_PORT")
+		assert.NotContains(t, err.Error(), "AGG_TIMEOUT")
+	})
+
+	t.Run("validators and groups", func(t *testing.T) {
+		type Config struct {
+			User     string `group:"auth"`
+			Password string `group:"auth"`
+		}
+
+		t.Setenv("AGGV_USER", "admin")
+
+		errFirst := errors.New("first")
+		errSecond := errors.New("second")
+
+		err := New(
+			WithPrefix("AGGV"),
+			WithErrorAggregation(),
+			WithRequiredGroups(),
+			WithValueValidator(func(any) error { return errFirst }),
+			WithValueValidator(func(any) error { return errSecond }),
+		).Load(&Config{})
+
+		assert.ErrorIs(t, err, ErrLoad)
+		assert.ErrorIs(t, err, ErrPartialGroup)
+		assert.ErrorIs(t, err, errFirst)
+		assert.ErrorIs(t, err, errSecond)
+	})
+
+	t.Run("no error", func(t *testing.T) {
+		type Config struct {
+			Name string
+		}
+
+		var cfg Config
+
+		assert.NoError(t, New(WithPrefix("AGG"), WithErrorAggregation()).Load(&cfg))
+		assert.Equal(t, "app", cfg.Name)
+	})
+}
@@ -0,0 +1,116 @@
+package goconfig
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// byteSizeUnits maps lower-cased byte size suffixes to their size. SI units
+// are powers of 1000 and binary units powers of 1024; single letters are SI,
+// as for configtype.ByteSize.
+var byteSizeUnits = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// isByteSize reports whether the field is tagged bytesize:"true".
+func (*Loader) isByteSize(tag reflect.StructTag) bool {
+	b, _ := strconv.ParseBool(tag.Get("bytesize"))
+	return b
+}
+
+// setByteSizeVal sets an integer field to the number of bytes of a human-readable
+// size such as "512", "10MB", "2G" or "1.5GiB". Units are case-insensitive.
+// Slices and maps pass the tag on, so their integer elements are sizes too.
+func (*Loader) setByteSizeVal(vf reflect.Value, raw string) error {
+	kind := vf.Kind()
+
+	isInt := kind >= reflect.Int && kind <= reflect.Int64
+	isUint := kind >= reflect.Uint && kind <= reflect.Uintptr
+
+	if !isInt && !isUint {
+		return errors.Errorf("bytesize tag requires an integer field, got %s", vf.Type())
+	}
+
+	n, err := parseByteSize(raw)
+	if err != nil {
+		return err
+	}
+
+	if isUint {
+		if vf.OverflowUint(n) {
+			return errors.Errorf("byte size %s overflows %s", raw, vf.Type())
+		}
+
+		vf.SetUint(n)
+
+		return nil
+	}
+
+	if n > math.MaxInt64 || vf.OverflowInt(int64(n)) {
+		return errors.Errorf("byte size %s overflows %s", raw, vf.Type())
+	}
+
+	vf.SetInt(int64(n))
+
+	return nil
+}
+
+// parseByteSize returns the number of bytes of a size such as "10MiB".
+func parseByteSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	size, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, errors.Errorf("unknown byte size unit %q", s[i:])
+	}
+
+	// whole numbers are multiplied exactly, fractions like "1.5GiB" go through float64
+	if n, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if n > math.MaxUint64/size {
+			return 0, errors.Errorf("byte size %q overflows uint64", s)
+		}
+
+		return n * size, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid byte size %q", s)
+	}
+
+	bytes := f * float64(size)
+	if bytes >= math.MaxUint64 {
+		return 0, errors.Errorf("byte size %q overflows uint64", s)
+	}
+
+	return uint64(bytes), nil
+}
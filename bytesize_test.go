@@ -0,0 +1,74 @@
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteSizeTag(t *testing.T) {
+	type Config struct {
+		MaxUpload int64            `env:"BS_MAX_UPLOAD" bytesize:"true"`
+		Buffer    uint32           `bytesize:"true"`
+		Cache     *int             `bytesize:"true"`
+		Chunk     int              `bytesize:"true"`
+		Limits    []uint64         `bytesize:"true"`
+		Quotas    map[string]int64 `bytesize:"true" format:"kv"`
+		Plain     int
+	}
+
+	t.Setenv("BS_MAX_UPLOAD", "10MB")
+	t.Setenv("BS_BUFFER", "512KiB")
+	t.Setenv("BS_CACHE", "2G")
+	t.Setenv("BS_CHUNK", "1.5 kib")
+	t.Setenv("BS_LIMITS", "1mib,100,3TB")
+	t.Setenv("BS_QUOTAS", "alice=1GiB,bob=500mb")
+	t.Setenv("BS_PLAIN", "42")
+
+	var cfg Config
+	require.NoError(t, New(WithPrefix("BS")).Load(&cfg))
+
+	assert.Equal(t, int64(10_000_000), cfg.MaxUpload)
+	assert.Equal(t, uint32(512*1024), cfg.Buffer)
+
+	if assert.NotNil(t, cfg.Cache) {
+		assert.Equal(t, 2_000_000_000, *cfg.Cache)
+	}
+
+	assert.Equal(t, 1536, cfg.Chunk)
+	assert.Equal(t, []uint64{1 << 20, 100, 3e12}, cfg.Limits)
+	assert.Equal(t, map[string]int64{"alice": 1 << 30, "bob": 500e6}, cfg.Quotas)
+	assert.Equal(t, 42, cfg.Plain)
+
+	for _, tt := range []struct {
+		key, raw, msg string
+	}{
+		{"BS_MAX_UPLOAD", "10XB", `unknown byte size unit "XB"`},
+		{"BS_MAX_UPLOAD", "MB", "invalid byte size"},
+		{"BS_MAX_UPLOAD", "-1MB", "unknown byte size unit"},
+		{"BS_BUFFER", "5GB", "overflows uint32"},
+		{"BS_MAX_UPLOAD", "20EB", "unknown byte size unit"},
+		{"BS_MAX_UPLOAD", "10000PB", "overflows int64"},
+		{"BS_LIMITS", "99999999999PiB", "overflows uint64"},
+	} {
+		t.Run(tt.raw, func(t *testing.T) {
+			t.Setenv(tt.key, tt.raw)
+
+			err := New(WithPrefix("BS")).Load(&Config{})
+			assert.ErrorContains(t, err, "cannot set field "+tt.key+" value")
+			assert.ErrorContains(t, err, tt.msg)
+		})
+	}
+
+	t.Run("not an integer", func(t *testing.T) {
+		type Config struct {
+			Size string `bytesize:"true"`
+		}
+
+		t.Setenv("BSS_SIZE", "10MB")
+
+		err := New(WithPrefix("BSS")).Load(&Config{})
+		assert.ErrorContains(t, err, "bytesize tag requires an integer field, got string")
+	})
+}
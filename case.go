@@ -0,0 +1,62 @@
+package goconfig
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Values of the "case" tag that normalize the case of string values.
+const (
+	// CaseUpper upper-cases the value, e.g. "eu-west" becomes "EU-WEST"
+	CaseUpper string = "upper"
+	// CaseLower lower-cases the value, e.g. "INFO" becomes "info"
+	CaseLower string = "lower"
+	// CaseTitle upper-cases the first letter of every word and lower-cases the
+	// others, e.g. "new YORK" becomes "New York"
+	CaseTitle string = "title"
+)
+
+// setStringVal sets a string value, normalizing its case as the optional
+// "case" tag requires.
+func (*Loader) setStringVal(fval reflect.Value, raw string, tag reflect.StructTag) error {
+	mode, ok := tag.Lookup("case")
+	if !ok {
+		fval.SetString(raw)
+		return nil
+	}
+
+	switch mode {
+	case CaseUpper:
+		raw = strings.ToUpper(raw)
+	case CaseLower:
+		raw = strings.ToLower(raw)
+	case CaseTitle:
+		raw = titleCase(raw)
+	default:
+		return errors.Errorf("invalid case tag %q, expected %s, %s or %s", mode, CaseUpper, CaseLower, CaseTitle)
+	}
+
+	fval.SetString(raw)
+
+	return nil
+}
+
+// titleCase upper-cases the letters that start a word, i.e. that follow a
+// character other than a letter or digit, and lower-cases the other letters.
+func titleCase(s string) string {
+	inWord := false
+
+	return strings.Map(func(r rune) rune {
+		start := !inWord
+		inWord = unicode.IsLetter(r) || unicode.IsDigit(r)
+
+		if start {
+			return unicode.ToUpper(r)
+		}
+
+		return unicode.ToLower(r)
+	}, s)
+}
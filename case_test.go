@@ -0,0 +1,45 @@
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseTag(t *testing.T) {
+	type Config struct {
+		Region   string   `case:"upper"`
+		Level    string   `case:"lower" oneof:"debug info"`
+		City     string   `case:"title"`
+		Zones    []string `case:"lower"`
+		Names    []string `case:"title" sep:";"`
+		Untagged string
+	}
+
+	t.Setenv("CT_REGION", "eu-west-1")
+	t.Setenv("CT_LEVEL", "INFO")
+	t.Setenv("CT_CITY", "new YORK-city o'hare")
+	t.Setenv("CT_ZONES", "A,b,C")
+	t.Setenv("CT_NAMES", "ada LOVELACE;élodie")
+	t.Setenv("CT_UNTAGGED", "MiXeD")
+
+	var cfg Config
+	err := New(WithPrefix("CT")).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EU-WEST-1", cfg.Region)
+	assert.Equal(t, "info", cfg.Level, "oneof is checked after the case is normalized")
+	assert.Equal(t, "New York-City O'Hare", cfg.City)
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Zones)
+	assert.Equal(t, []string{"Ada Lovelace", "Élodie"}, cfg.Names)
+	assert.Equal(t, "MiXeD", cfg.Untagged)
+
+	t.Run("invalid mode", func(t *testing.T) {
+		type Config struct {
+			Region string `case:"camel"`
+		}
+
+		err := New(WithPrefix("CT")).Load(&Config{})
+		assert.ErrorContains(t, err, `invalid case tag "camel"`)
+	})
+}
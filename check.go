@@ -0,0 +1,193 @@
+package goconfig
+
+import (
+	stderrors "errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedType is returned by Check for fields whose type cannot be loaded.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrMalformedTag is returned by Check for struct tags that cannot be parsed.
+	ErrMalformedTag = errors.New("malformed struct tag")
+	// ErrConflictingTags is returned by Check for fields with conflicting tags
+	// or env keys shared by several fields.
+	ErrConflictingTags = errors.New("conflicting tags")
+)
+
+// Check verifies that the struct type of s can be loaded, without reading the environment.
+// s can be a struct or a pointer to a struct. Check walks the type and reports fields with
+// unsupported types (ErrUnsupportedType), malformed struct tags (ErrMalformedTag), fields
+// having both env and alias tags or env keys used by several fields (ErrConflictingTags).
+// All problems are returned joined together.
+func (c *Loader) Check(s any) error {
+	t := reflect.TypeOf(s)
+	if t == nil || c.getDirectType(t).Kind() != reflect.Struct {
+		return errors.Errorf("should be a struct or a pointer to a struct, got %T", s)
+	}
+
+	ck := &checker{
+		loader:  c,
+		keys:    map[string]string{},
+		visited: map[reflect.Type]bool{},
+	}
+	ck.checkStruct(c.getDirectType(t), scope{})
+
+	return stderrors.Join(ck.errs...)
+}
+
+// checkSupportedTypes returns the ErrUnsupportedType problems Check finds in
+// the struct type of s. It is run before loading by WithFailFastOnUnsupportedType.
+func (c *Loader) checkSupportedTypes(s any) error {
+	err := c.Check(s)
+	if err == nil {
+		return nil
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return err
+	}
+
+	var unsupported []error
+
+	for _, e := range joined.Unwrap() {
+		if errors.Is(e, ErrUnsupportedType) {
+			unsupported = append(unsupported, e)
+		}
+	}
+
+	return stderrors.Join(unsupported...)
+}
+
+// checker accumulates the problems found while walking a struct type.
+type checker struct {
+	loader *Loader
+	// keys maps env keys to the path of the field using them
+	keys map[string]string
+	// visited holds the struct types being walked, to stop on recursive types
+	visited map[reflect.Type]bool
+	errs    []error
+}
+
+func (ck *checker) checkStruct(t reflect.Type, sc scope) {
+	if ck.visited[t] {
+		return
+	}
+
+	ck.visited[t] = true
+	defer delete(ck.visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		ck.checkField(t.Field(i), sc)
+	}
+}
+
+func (ck *checker) checkField(tf reflect.StructField, sc scope) {
+	c := ck.loader
+
+	if !tf.IsExported() || c.isIgnored(tf) {
+		return
+	}
+
+	tf, depth := c.limitKeyDepth(tf, sc)
+	envKey, nPrefix := c.buildEnvKey(tf, sc.keys)
+	nScope := scope{keys: nPrefix, path: c.buildFieldPath(tf, sc.path), depth: depth}
+	fieldPath := strings.Join(nScope.path, ".")
+
+	if err := validateTag(string(tf.Tag)); err != nil {
+		ck.errs = append(ck.errs, errors.Wrapf(ErrMalformedTag, "field %s: %v", fieldPath, err))
+	}
+
+	_, hasEnv := tf.Tag.Lookup("env")
+	_, hasAlias := tf.Tag.Lookup("alias")
+
+	if hasEnv && hasAlias {
+		ck.errs = append(ck.errs, errors.Wrapf(ErrConflictingTags, "field %s has both env and alias tags", fieldPath))
+	}
+
+	t := c.getDirectType(tf.Type)
+
+	if c.isNestedStruct(tf) {
+		ck.checkStruct(t, nScope)
+		return
+	}
+
+	if other, ok := ck.keys[envKey]; ok {
+		ck.errs = append(ck.errs, errors.Wrapf(ErrConflictingTags, "fields %s and %s use the same key %s", other, fieldPath, envKey))
+	} else {
+		ck.keys[envKey] = fieldPath
+	}
+
+	if !c.isSupportedType(t) {
+		ck.errs = append(ck.errs, errors.Wrapf(ErrUnsupportedType, "field %s (%s)", fieldPath, tf.Type))
+	}
+}
+
+// isSupportedType reports whether setFieldVal can parse a value of type t.
+func (c *Loader) isSupportedType(t reflect.Type) bool {
+	t = c.getDirectType(t)
+
+	if c.isLeafType(t) {
+		return true
+	}
+
+	switch kind := t.Kind(); {
+	case c.isString(kind), c.isBool(kind), c.isInt(kind), c.isUint(kind), c.isFloat(kind), c.isStruct(kind):
+		return true
+	case c.isSliceField(kind):
+		return c.isSupportedType(t.Elem())
+	case c.isMap(kind):
+		return c.isSupportedType(t.Key()) && (c.isAny(t.Elem()) || c.isSupportedType(t.Elem()))
+	default:
+		return false
+	}
+}
+
+// validateTag checks that a struct tag follows the conventional
+// `key:"value" key2:"value2"` format, which reflect.StructTag silently ignores otherwise.
+func validateTag(tag string) error {
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return errors.Errorf("bad syntax in %q", tag)
+		}
+
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		// scan the quoted value
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+
+		if i >= len(tag) {
+			return errors.Errorf("unterminated value for key %q", name)
+		}
+
+		if _, err := strconv.Unquote(tag[:i+1]); err != nil {
+			return errors.Errorf("bad value for key %q", name)
+		}
+
+		tag = tag[i+1:]
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package goconfig
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("supported struct", func(t *testing.T) {
+		loader := New()
+
+		assert.NoError(t, loader.Check(&BasicConfig{}))
+		assert.NoError(t, loader.Check(NestedConfig{}))
+		assert.NoError(t, loader.Check(&PointerConfig{}))
+	})
+
+	t.Run("unsupported field types", func(t *testing.T) {
+		type Config struct {
+			Name     string
+			Events   chan string
+			Callback func()
+			DB       struct {
+				Weights []complex64
+				Timeout time.Duration
+			}
+			Labels map[string]any
+			hidden chan int
+		}
+
+		err := New().Check(&Config{})
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+		assert.ErrorContains(t, err, "field Events (chan string)")
+		assert.ErrorContains(t, err, "field Callback (func())")
+		assert.ErrorContains(t, err, "field DB.Weights ([]complex64)")
+		assert.NotContains(t, err.Error(), "Labels")
+		assert.NotContains(t, err.Error(), "hidden")
+	})
+
+	t.Run("malformed and conflicting tags", func(t *testing.T) {
+		// built with reflection since go vet rejects malformed tags in source
+		config := reflect.New(reflect.StructOf([]reflect.StructField{
+			{Name: "Host", Type: reflect.TypeOf(""), Tag: `env:HOST`},
+			{Name: "Port", Type: reflect.TypeOf(0), Tag: `env:"PORT" alias:"SERVER_PORT"`},
+			{Name: "Address", Type: reflect.TypeOf(""), Tag: `env:"HOST_ADDR"`},
+			{Name: "Addr", Type: reflect.TypeOf(""), Tag: `env:"HOST_ADDR"`},
+		})).Interface()
+
+		err := New().Check(config)
+		assert.ErrorIs(t, err, ErrMalformedTag)
+		assert.ErrorIs(t, err, ErrConflictingTags)
+		assert.ErrorContains(t, err, "field Host")
+		assert.ErrorContains(t, err, "field Port has both env and alias tags")
+		assert.ErrorContains(t, err, "fields Address and Addr use the same key HOST_ADDR")
+	})
+
+	t.Run("recursive type", func(t *testing.T) {
+		type Node struct {
+			Name string
+			Next *Node
+		}
+
+		type Config struct {
+			Root Node
+		}
+
+		assert.NoError(t, New().Check(&Config{}))
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		assert.Error(t, New().Check("config"))
+		assert.Error(t, New().Check(nil))
+	})
+}
+
+func TestFailFastOnUnsupportedType(t *testing.T) {
+	type Inner struct {
+		Callback func()
+	}
+
+	type Config struct {
+		Name   string
+		Events chan string
+		Inner  *Inner
+		Dup1   string `env:"FF_DUP"`
+		Dup2   string `env:"FF_DUP"`
+	}
+
+	t.Setenv("NAME", "api")
+
+	t.Run("without fail fast", func(t *testing.T) {
+		var cfg Config
+		assert.NoError(t, New().Load(&cfg))
+		assert.Equal(t, "api", cfg.Name)
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		var cfg Config
+		err := New(WithFailFastOnUnsupportedType()).Load(&cfg)
+
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+		assert.ErrorContains(t, err, "field Events (chan string)")
+		assert.ErrorContains(t, err, "field Inner.Callback (func())")
+		assert.NotErrorIs(t, err, ErrConflictingTags)
+		assert.Empty(t, cfg.Name, "nothing is loaded")
+	})
+
+	t.Run("supported struct", func(t *testing.T) {
+		type Supported struct {
+			Name string
+		}
+
+		assert.NoError(t, New(WithFailFastOnUnsupportedType()).Load(&Supported{}))
+	})
+}
@@ -1,14 +1,23 @@
 package goconfig
 
 import (
+	"context"
 	"encoding"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/jkaveri/goconfig/internal/deepcopy"
 	"github.com/pkg/errors"
 )
 
@@ -17,6 +26,15 @@ const (
 	DefaultSep string = "_"
 	// DefaultArrSep is the default separator used for array values
 	DefaultArrSep string = ","
+	// FormatKV is the value of the "format" tag that makes a map field
+	// parse "key=value" pairs instead of JSON
+	FormatKV string = "kv"
+	// FormatQuery is the value of the "format" tag that makes a struct field
+	// parse a URL query string such as "host=localhost&port=5432"
+	FormatQuery string = "query"
+	// FormatHex is the value of the "format" tag that makes a []byte field
+	// hex-decode its value, e.g. "deadbeef"
+	FormatHex string = "hex"
 )
 
 // Load loads configuration from environment variables into the provided struct.
@@ -51,18 +69,134 @@ type Loader struct {
 	sep                  string
 	arraySep             string
 	fieldNameTransformer func(name string) string
+	onMissing            func(fieldPath, envKey string)
+	legacyKeys           map[string][]string
+	parsers              map[reflect.Type]func(raw string) (any, error)
+	sliceSuffixes        SuffixSequence
+	defaults             any
+	valueTransformer     func(key, raw string) string
+	prefixCaseFold       bool
+	kvSource             KVSource
+	kvPrecedence         Precedence
+	ctx                  context.Context
+	mergeCollections     bool
+	stdin                io.Reader
+	keyDepthLimit        int
+	accessors            map[reflect.Type]reflect.Value
+	validators           []func(s any) error
+	aliasSep             string
+	deprecationHandler   func(key, message string)
+	boolLiterals         map[string]bool
+	failFast             bool
+	sliceTrimEmpty       bool
+	requiredGroups       bool
+	argOverrides         map[string]string
+	structInit           func(t reflect.Type) (any, bool)
+	envKeyNormalizer     func(key string) string
+	aggregateErrors      bool
+	kvBareKeys           bool
+	timeLayout           string
+	globalDefault        func(fieldPath string) (string, bool)
+	sources              []Source
+	strictPrefix         bool
+	initEmptyMaps        bool
+	initEmptySlices      bool
+
+	// state is the state of the current load, see Load
+	state *loadState
 }
 
 // Load loads environment variables into the provided struct.
 // The struct should be a pointer to a struct with fields tagged with "env" or "alias" tags.
 // Returns an error if the loading process fails.
 func (c *Loader) Load(s any) error {
-	_, err := c.recursiveLoadToStruct(s, nil)
-	return err
+	// each load works on a copy holding its own state, so a Loader can be shared
+	l := *c
+	l.state = &loadState{}
+
+	return l.loadWithState(s)
+}
+
+// LoadPrefixed loads the variables under prefix into s like Load, e.g. for a
+// plugin owning a namespace. The prefix is joined to the prefix of the Loader
+// with the separator, so with WithPrefix("APP") LoadPrefixed("CACHE", &sub)
+// reads APP_CACHE_SIZE into sub.Size. The Loader itself is not changed.
+func (c *Loader) LoadPrefixed(prefix string, s any) error {
+	l := *c
+	l.prefix = c.joinKeys(prefix)
+
+	return l.Load(s)
+}
+
+func (c *Loader) loadWithState(s any) error {
+	if c.failFast {
+		if err := c.checkSupportedTypes(s); err != nil {
+			return err
+		}
+	}
+
+	if err := c.applyDefaults(s); err != nil {
+		return err
+	}
+
+	if _, err := c.recursiveLoadToStruct(s, scope{}); err != nil {
+		return err
+	}
+
+	if err := c.checkUnknownVariables(); err != nil && !c.collectErr(err) {
+		return err
+	}
+
+	if err := c.checkGroups(); err != nil && !c.collectErr(err) {
+		return err
+	}
+
+	if err := c.validate(s); err != nil {
+		return err
+	}
+
+	return c.loadErr()
+}
+
+// applyDefaults copies the prototype registered with WithDefaults into s.
+func (c *Loader) applyDefaults(s any) error {
+	if c.defaults == nil {
+		return nil
+	}
+
+	target := reflect.ValueOf(s)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return errors.Errorf("should be a pointer to %T", s)
+	}
+
+	proto := reflect.ValueOf(c.defaults)
+	if proto.Kind() == reflect.Pointer {
+		proto = proto.Elem()
+	}
+
+	if proto.Type() != target.Elem().Type() {
+		return errors.Errorf("defaults type %s does not match %s", proto.Type(), target.Elem().Type())
+	}
+
+	target.Elem().Set(deepcopy.Value(proto))
+
+	return nil
+}
+
+// scope describes the position of a struct inside the loaded struct.
+type scope struct {
+	// keys are the env key segments leading to the struct
+	keys []string
+	// path are the Go field names leading to the struct
+	path []string
+	// parent is the struct holding the fields being loaded
+	parent reflect.Value
+	// depth is the number of key segments added by the fields leading to the struct
+	depth int
 }
 
 // nolint:gocyclo
-func (c *Loader) recursiveLoadToStruct(s any, prefix []string) (found bool, err error) {
+func (c *Loader) recursiveLoadToStruct(s any, sc scope) (found bool, err error) {
 	vPtr := reflect.ValueOf(s)
 
 	if vPtr.Kind() != reflect.Ptr {
@@ -73,7 +207,7 @@ func (c *Loader) recursiveLoadToStruct(s any, prefix []string) (found bool, err
 		if p := recover(); p != nil {
 			err = errors.Errorf(
 				"cannot load to struct %T (prefix=%s). panic: %v",
-				s, strings.Join(prefix, c.sep), p,
+				s, strings.Join(sc.keys, c.sep), p,
 			)
 		}
 	}()
@@ -81,232 +215,1136 @@ func (c *Loader) recursiveLoadToStruct(s any, prefix []string) (found bool, err
 	return c.loopOverFields(
 		c.getDirectType(reflect.TypeOf(s)),
 		vPtr.Elem(),
-		prefix,
+		sc,
 	)
 }
 
-func (c *Loader) loopOverFields(
-	t reflect.Type,
-	v reflect.Value,
-	prefix []string,
-) (bool, error) {
-	n := v.NumField()
-	found := false
+func (c *Loader) loopOverFields(
+	t reflect.Type,
+	v reflect.Value,
+	sc scope,
+) (bool, error) {
+	n := v.NumField()
+	found := false
+	sc.parent = v
+
+	for i := 0; i < n; i++ {
+		foundField, err := c.loadToField(
+			t.Field(i),
+			v.Field(i),
+			sc,
+		)
+		if err != nil && !c.collectErr(err) {
+			return false, err
+		}
+
+		if foundField {
+			found = true
+		}
+	}
+
+	return found, nil
+}
+
+func (c *Loader) loadToField(
+	tf reflect.StructField,
+	vf reflect.Value,
+	sc scope,
+) (found bool, err error) {
+	if !vf.CanSet() || c.isIgnored(tf) {
+		return false, nil
+	}
+
+	tf, depth := c.limitKeyDepth(tf, sc)
+	t := c.getDirectType(tf.Type)
+	envKey, nPrefix := c.buildEnvKey(tf, sc.keys)
+
+	if by, ok := tf.Tag.Lookup("enabledby"); ok {
+		if enabled, err := c.isEnabled(tf, by, sc); err != nil || !enabled {
+			return false, err
+		}
+	}
+
+	if from, ok := tf.Tag.Lookup("prefixfrom"); ok && c.isNestedStruct(tf) {
+		var use bool
+		if envKey, nPrefix, use, err = c.buildDiscriminatedKey(tf, from, sc); err != nil || !use {
+			return false, err
+		}
+	}
+
+	nScope := scope{keys: nPrefix, path: c.buildFieldPath(tf, sc.path), depth: depth}
+
+	if groups, ok := tf.Tag.Lookup("group"); ok && c.requiredGroups {
+		defer func() {
+			c.state.addGroupMember(groups, envKey, found)
+		}()
+	}
+
+	envKey, envVal, exist, err := c.lookupAliases(tf, sc.keys, envKey)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = errors.Errorf(
+				"cannot load to %s field (prefix=%s). panic: %v",
+				t.String(),
+				strings.Join(nPrefix, c.sep),
+				p,
+			)
+		}
+	}()
+
+	if exist && envVal == StdinSentinel && c.isStdinTagged(tf) {
+		if envVal, err = c.readStdin(); err != nil {
+			return false, errors.Wrapf(err, "cannot read field %s value from stdin", envKey)
+		}
+	}
+
+	prev := c.collectionToMerge(vf)
+
+	if exist {
+		c.warnDeprecated(tf, envKey)
+
+		set, err1 := c.setValue(tf, vf, c.transformValue(envKey, envVal))
+		if err1 != nil {
+			return false, errors.Wrapf(err1, "cannot set field %s value", envKey)
+		}
+
+		if set {
+			c.mergeCollection(vf, prev)
+			return true, c.validateField(vf, tf.Tag, envKey)
+		}
+	}
+
+	if !exist && c.sliceSuffixes != nil && c.isSliceField(t.Kind()) {
+		found, err = c.setIndexedSliceVal(vf, envKey, nScope, tf.Tag)
+		if err != nil {
+			return false, errors.Wrapf(err, "cannot set field %s value", envKey)
+		}
+
+		if found {
+			c.mergeCollection(vf, prev)
+			return true, c.validateField(vf, tf.Tag, envKey)
+		}
+	}
+
+	if c.isNestedStruct(tf) {
+		if found, err = c.setStructVal(vf, nScope); err != nil || found || tf.Anonymous {
+			return found, err
+		}
+
+		// a required struct needs one of its fields, or a default
+		return false, c.checkRequired(tf, vf, envKey)
+	}
+
+	if !exist && c.onMissing != nil {
+		c.onMissing(strings.Join(nScope.path, "."), envKey)
+	}
+
+	if !exist {
+		if err := c.applyGlobalDefault(tf, vf, strings.Join(nScope.path, "."), envKey); err != nil {
+			return false, err
+		}
+
+		if err := c.checkRequired(tf, vf, envKey); err != nil {
+			return false, err
+		}
+
+		c.initEmptyCollection(vf)
+	}
+
+	return false, nil
+}
+
+// lookupEnv looks up the env key, falling back to the legacy keys
+// registered for it with WithKeyRename.
+func (c *Loader) lookupEnv(key string) (string, bool, error) {
+	if v, ok, err := c.lookupKey(key); ok || err != nil {
+		return v, ok, err
+	}
+
+	for _, legacy := range c.legacyKeys[key] {
+		if v, ok, err := c.lookupKey(legacy); ok || err != nil {
+			return v, ok, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// getenv looks up a single key in the process environment, folding the case
+// of its prefix when WithPrefixCaseFold is enabled and normalizing it when
+// WithEnvKeyNormalizer is set, in case the exact key is not set.
+func (c *Loader) getenv(key string) (string, bool) {
+	if v, ok := os.LookupEnv(key); ok {
+		return v, true
+	}
+
+	if v, ok := c.getenvPrefixFold(key); ok {
+		return v, true
+	}
+
+	if c.envKeyNormalizer != nil {
+		v, ok := c.envIndex()[c.envKeyNormalizer(key)]
+		return v, ok
+	}
+
+	return "", false
+}
+
+// getenvPrefixFold looks up key with the case of its prefix folded.
+func (c *Loader) getenvPrefixFold(key string) (string, bool) {
+	if !c.prefixCaseFold || c.prefix == "" || !strings.HasPrefix(key, c.prefix) {
+		return "", false
+	}
+
+	rest := key[len(c.prefix):]
+	if rest != "" && !strings.HasPrefix(rest, c.sep) {
+		return "", false
+	}
+
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		if len(k) == len(key) && strings.HasSuffix(k, rest) && strings.EqualFold(k[:len(c.prefix)], c.prefix) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// setValue sets the field from the raw value found in the environment.
+// It returns false when the value does not apply to the field, e.g. a struct
+// that has to be loaded field by field.
+func (c *Loader) setValue(tf reflect.StructField, vf reflect.Value, envVal string) (bool, error) {
+	t := c.getDirectType(tf.Type)
+
+	if c.isEmbeddedJSON(tf, t, envVal) {
+		return true, c.setJSONVal(vf, envVal)
+	}
+
+	if c.isQueryStruct(tf) {
+		return true, c.setQueryVal(vf, envVal)
+	}
+
+	// a leaf tagged struct is only read from its own variable, as JSON
+	if c.isLeafTagged(tf) && c.isStruct(t.Kind()) && !c.isLeafType(t) {
+		return true, c.setJSONVal(vf, envVal)
+	}
+
+	return c.setFieldVal(vf, envVal, tf.Tag)
+}
+
+// validateField checks the constraints declared in the tag against the loaded value.
+func (c *Loader) validateField(vf reflect.Value, tag reflect.StructTag, envKey string) error {
+	vf = c.getDirectVal(vf)
+
+	if c.isSliceField(vf.Kind()) {
+		if err := c.checkSliceLen(vf.Len(), tag); err != nil {
+			return errors.Wrapf(err, "invalid field %s value", envKey)
+		}
+	}
+
+	if err := c.checkOneOf(vf, tag); err != nil {
+		return errors.Wrapf(err, "invalid field %s value", envKey)
+	}
+
+	return nil
+}
+
+// checkOneOf validates the value against the optional "oneof" tag, a space
+// separated list of allowed values compared with the value's text form.
+// Each element of a slice is checked on its own.
+func (c *Loader) checkOneOf(vf reflect.Value, tag reflect.StructTag) error {
+	oneof, ok := tag.Lookup("oneof")
+	if !ok {
+		return nil
+	}
+
+	allowed := strings.Fields(oneof)
+
+	if !c.isSliceField(vf.Kind()) {
+		if v := fmt.Sprint(vf.Interface()); !slices.Contains(allowed, v) {
+			return errors.Errorf("%q is not one of [%s]", v, oneof)
+		}
+
+		return nil
+	}
+
+	for i := 0; i < vf.Len(); i++ {
+		if v := fmt.Sprint(c.getDirectVal(vf.Index(i)).Interface()); !slices.Contains(allowed, v) {
+			return errors.Errorf("element %d %q is not one of [%s]", i, v, oneof)
+		}
+	}
+
+	return nil
+}
+
+// checkSliceLen validates the length of a slice against the optional
+// "len", "minlen" and "maxlen" tags.
+func (*Loader) checkSliceLen(n int, tag reflect.StructTag) error {
+	constraints := []struct {
+		name string
+		fail func(limit int) bool
+		msg  string
+	}{
+		{"len", func(limit int) bool { return n != limit }, "expected length %d, got %d"},
+		{"minlen", func(limit int) bool { return n < limit }, "expected at least %d elements, got %d"},
+		{"maxlen", func(limit int) bool { return n > limit }, "expected at most %d elements, got %d"},
+	}
+
+	for _, cons := range constraints {
+		raw, ok := tag.Lookup(cons.name)
+		if !ok {
+			continue
+		}
+
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s tag %q", cons.name, raw)
+		}
+
+		if cons.fail(limit) {
+			return errors.Errorf(cons.msg, limit, n)
+		}
+	}
+
+	return nil
+}
+
+// isEmbeddedJSON reports whether the value found at the key of an anonymous
+// embedded struct is a JSON object that fills the whole struct at once.
+func (c *Loader) isEmbeddedJSON(tf reflect.StructField, t reflect.Type, envVal string) bool {
+	return tf.Anonymous &&
+		c.isStruct(t.Kind()) &&
+		!c.isLeafType(t) &&
+		strings.HasPrefix(strings.TrimSpace(envVal), "{")
+}
+
+// transformValue applies the value transformer set with WithValueTransformer.
+func (c *Loader) transformValue(key, raw string) string {
+	if c.valueTransformer == nil {
+		return raw
+	}
+
+	return c.valueTransformer(key, raw)
+}
+
+// buildFieldPath appends the field name to the Go field path.
+// Anonymous fields are skipped since their fields are promoted.
+func (*Loader) buildFieldPath(tf reflect.StructField, path []string) []string {
+	if tf.Anonymous {
+		return path
+	}
+
+	return append(path[:len(path):len(path)], tf.Name)
+}
+
+// isIgnored reports whether the field is tagged env:"-", like json:"-", so it is
+// never loaded, nor its fields when it is a struct.
+func (*Loader) isIgnored(tf reflect.StructField) bool {
+	return tf.Tag.Get("env") == "-"
+}
+
+func (c *Loader) getFieldName(tf reflect.StructField) (name string, exactly bool) {
+	if tag, ok := tf.Tag.Lookup("env"); ok {
+		return envNames(tag)[0], true
+	}
+
+	// use alias name instead of field name
+	if tag, ok := tf.Tag.Lookup("alias"); ok {
+		return c.aliasNames(tag)[0], false
+	}
+
+	name = tf.Name
+	if c.fieldNameTransformer != nil {
+		name = c.fieldNameTransformer(name)
+	}
+
+	return name, false
+}
+
+func (c *Loader) buildEnvKey(tf reflect.StructField, parentKeys []string) (string, []string) {
+	if tf.Anonymous || c.isSquashed(tf) {
+		return c.joinKeys(parentKeys...), parentKeys
+	}
+
+	name, exactly := c.getFieldName(tf)
+	parentKeys = append(parentKeys, name)
+
+	if !exactly {
+		return c.joinKeys(parentKeys...), parentKeys
+	}
+
+	return name, parentKeys
+}
+
+// aliasNames splits an alias tag into its names when WithStructTagAliasSeparator
+// is used. It always returns at least one name.
+func (c *Loader) aliasNames(tag string) []string {
+	if c.aliasSep == "" {
+		return []string{tag}
+	}
+
+	var names []string
+
+	for _, name := range strings.Split(tag, c.aliasSep) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return []string{""}
+	}
+
+	return names
+}
+
+// envNames splits an env tag into its comma separated keys, e.g.
+// env:"PORT,SERVER_PORT" into PORT and SERVER_PORT. It always returns at least one key.
+func envNames(tag string) []string {
+	var names []string
+
+	for _, name := range strings.Split(tag, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return []string{tag}
+	}
+
+	return names
+}
+
+// lookupAliases looks up envKey and, when it is not set, the other keys of an env
+// tag or the keys built from the other names of a list alias tag, in order.
+// It returns the key that was found.
+func (c *Loader) lookupAliases(
+	tf reflect.StructField,
+	parentKeys []string,
+	envKey string,
+) (string, string, bool, error) {
+	v, ok, err := c.lookupEnv(envKey)
+	if ok || err != nil {
+		return envKey, v, ok, err
+	}
+
+	if _, discriminated := tf.Tag.Lookup("prefixfrom"); discriminated || tf.Anonymous || c.isSquashed(tf) {
+		return envKey, "", false, nil
+	}
+
+	if tag, ok := tf.Tag.Lookup("env"); ok {
+		for _, key := range envNames(tag)[1:] {
+			if v, ok, err := c.lookupEnv(key); ok || err != nil {
+				return key, v, ok, err
+			}
+		}
+
+		return envKey, "", false, nil
+	}
+
+	tag, isAlias := tf.Tag.Lookup("alias")
+	if !isAlias || c.aliasSep == "" {
+		return envKey, "", false, nil
+	}
+
+	for _, name := range c.aliasNames(tag)[1:] {
+		key := c.joinKeys(append(parentKeys[:len(parentKeys):len(parentKeys)], name)...)
+
+		if v, ok, err := c.lookupEnv(key); ok || err != nil {
+			return key, v, ok, err
+		}
+	}
+
+	return envKey, "", false, nil
+}
+
+// joinKeys joins the prefix and the non-empty names with the separator.
+func (c *Loader) joinKeys(names ...string) string {
+	arr := []string{}
+
+	if c.prefix != "" {
+		arr = append(arr, c.prefix)
+	}
+
+	for _, name := range names {
+		// skip empty name
+		if name == "" {
+			continue
+		}
+
+		arr = append(arr, name)
+	}
+
+	return strings.Join(arr, c.sep)
+}
+
+// buildDiscriminatedKey builds the keys of a struct field tagged prefixfrom:"Field",
+// whose own key segment is the value of the sibling string field Field, e.g. REDIS
+// for STORE=redis. The sibling must be declared before the field so it is loaded first.
+// It returns false when the sibling is empty, in which case the field is not loaded.
+func (c *Loader) buildDiscriminatedKey(
+	tf reflect.StructField,
+	from string,
+	sc scope,
+) (string, []string, bool, error) {
+	df, ok := sc.parent.Type().FieldByName(from)
+	if !ok || len(df.Index) != 1 || df.Type.Kind() != reflect.String {
+		return "", nil, false, errors.Errorf("prefixfrom field %s of %s must be a string field of the same struct", from, tf.Name)
+	}
+
+	if df.Index[0] >= tf.Index[0] {
+		return "", nil, false, errors.Errorf("prefixfrom field %s must be declared before %s", from, tf.Name)
+	}
+
+	value := sc.parent.FieldByIndex(df.Index).String()
+	if value == "" {
+		return "", nil, false, nil
+	}
+
+	if c.fieldNameTransformer != nil {
+		value = c.fieldNameTransformer(value)
+	}
+
+	keys := append(sc.keys[:len(sc.keys):len(sc.keys)], value)
+
+	return c.joinKeys(keys...), keys, true, nil
+}
+
+// isEnabled reports whether the bool field named by the enabledby tag of tf is
+// true. The bool field must be declared before tf in the same struct so that it
+// is already loaded.
+func (*Loader) isEnabled(tf reflect.StructField, by string, sc scope) (bool, error) {
+	bf, ok := sc.parent.Type().FieldByName(by)
+	if !ok || len(bf.Index) != 1 || bf.Type.Kind() != reflect.Bool {
+		return false, errors.Errorf("enabledby field %s of %s must be a bool field of the same struct", by, tf.Name)
+	}
+
+	if bf.Index[0] >= tf.Index[0] {
+		return false, errors.Errorf("enabledby field %s must be declared before %s", by, tf.Name)
+	}
+
+	return sc.parent.FieldByIndex(bf.Index).Bool(), nil
+}
+
+// isSquashed reports whether a named struct field is tagged squash:"true",
+// which joins its fields to the parent's keys like an anonymous embedded struct.
+func (c *Loader) isSquashed(tf reflect.StructField) bool {
+	squash, _ := strconv.ParseBool(tf.Tag.Get("squash"))
+	t := c.getDirectType(tf.Type)
+
+	return squash && c.isStruct(t.Kind()) && !c.isLeafType(t)
+}
+
+// limitKeyDepth returns the depth of the fields of the struct field tf, and tf
+// tagged leaf:"true" when they would be deeper than the limit set with
+// WithKeyDepthLimit, so the struct is read from its own variable instead.
+// Embedded and squashed structs add no key segment and keep the depth.
+func (c *Loader) limitKeyDepth(tf reflect.StructField, sc scope) (reflect.StructField, int) {
+	depth := sc.depth + 1
+	if tf.Anonymous || c.isSquashed(tf) {
+		depth = sc.depth
+	}
+
+	if c.keyDepthLimit > 0 && depth >= c.keyDepthLimit && c.isNestedStruct(tf) {
+		// Lookup returns the first leaf key, so prepending overrides the field's tag
+		tf.Tag = `leaf:"true" ` + tf.Tag
+	}
+
+	return tf, depth
+}
+
+// isLeafTagged reports whether the field is tagged leaf:"true", which stops the
+// Loader from generating keys for the fields of a nested struct.
+func (*Loader) isLeafTagged(tf reflect.StructField) bool {
+	leaf, _ := strconv.ParseBool(tf.Tag.Get("leaf"))
+	return leaf
+}
+
+// isNestedStruct reports whether the field is a struct loaded field by field,
+// one key per field under the field's own key.
+func (c *Loader) isNestedStruct(tf reflect.StructField) bool {
+	t := c.getDirectType(tf.Type)
+	return c.isStruct(t.Kind()) && !c.isLeafType(t) && !c.isLeafTagged(tf) && !c.isQueryStruct(tf)
+}
+
+// isQueryStruct reports whether the field is a struct tagged format:"query",
+// which is read from a single URL query string instead of one key per field.
+func (c *Loader) isQueryStruct(tf reflect.StructField) bool {
+	t := c.getDirectType(tf.Type)
+	return tf.Tag.Get("format") == FormatQuery && c.isStruct(t.Kind()) && !c.isLeafType(t)
+}
+
+func (c *Loader) isTextUnmarshaler(fval reflect.Value) (encoding.TextUnmarshaler, bool) {
+	u, ok := c.addrInterface(fval).(encoding.TextUnmarshaler)
+	return u, ok
+}
+
+// addrInterface returns a pointer to fval as an interface so its methods with
+// a pointer receiver can be asserted, or nil when fval is not addressable.
+func (*Loader) addrInterface(fval reflect.Value) any {
+	if fval.Kind() != reflect.Ptr && !fval.CanAddr() {
+		return nil
+	}
+
+	if fval.Kind() != reflect.Ptr {
+		fval = fval.Addr()
+	}
+
+	if fval.Type().NumMethod() == 0 || !fval.CanInterface() {
+		return nil
+	}
+
+	return fval.Interface()
+}
+
+// isLeafType reports whether values of type t are parsed from a single value
+// instead of being loaded field by field.
+func (c *Loader) isLeafType(t reflect.Type) bool {
+	_, parsed := c.parsers[t]
+	_, accessed := c.accessors[t]
+
+	return parsed || accessed || c.isTextUnmarshalerType(t) || c.isTextUnmarshalerContextType(t) ||
+		t == locationType.Elem() || t == ipNetType || t == urlType
+}
+
+// isTextUnmarshalerType reports whether a pointer to t implements encoding.TextUnmarshaler.
+func (*Loader) isTextUnmarshalerType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
+
+func (c *Loader) setFieldVal(fval reflect.Value, envVal string, tag reflect.StructTag) (set bool, err error) {
+	if !fval.CanSet() {
+		return false, errors.Errorf("%s field is cannot be set", fval.Type().Name())
+	}
+
+	// *time.Location is set as a whole since time.LoadLocation returns a shared pointer
+	if c.isLocation(fval.Type()) {
+		return true, c.setLocationVal(fval, envVal)
+	}
+
+	for fval.Kind() == reflect.Pointer {
+		if fval.IsNil() {
+			fval.Set(reflect.New(fval.Type().Elem()))
+		}
+
+		fval = fval.Elem()
+	}
+	kind := fval.Kind()
+
+	if parse, ok := c.parsers[fval.Type()]; ok {
+		return true, c.setParsedVal(fval, envVal, parse)
+	}
+
+	if set, ok := c.accessors[fval.Type()]; ok {
+		return true, c.setAccessorVal(fval, envVal, set)
+	}
+
+	if c.isTime(fval.Type()) {
+		return true, c.setTimeVal(fval, envVal, tag)
+	}
+
+	if v, ok := c.addrInterface(fval).(TextUnmarshalerContext); ok {
+		return true, v.UnmarshalTextCtx(c.context(), []byte(envVal))
+	}
+
+	if v, ok := c.isTextUnmarshaler(fval); ok {
+		return true, v.UnmarshalText([]byte(envVal))
+	}
+
+	switch {
+	case c.isByteSize(tag) && !c.isSliceField(kind) && !c.isMap(kind):
+		return true, c.setByteSizeVal(fval, envVal)
+	case c.isHexBytes(fval.Type(), tag):
+		return true, c.setHexBytesVal(fval, envVal)
+	case c.isString(kind):
+		return true, c.setStringVal(fval, envVal, tag)
+	case c.isBool(kind):
+		return true, c.setBoolVal(fval, envVal)
+	case c.isIPNet(fval.Type()):
+		return true, c.setIPNetVal(fval, envVal)
+	case c.isURL(fval.Type()):
+		return true, c.setURLVal(fval, envVal)
+	case c.isDuration(fval):
+		return true, c.setDurationVal(fval, envVal, tag)
+	case c.isInt(kind):
+		return true, c.setIntVal(fval, envVal)
+	case c.isUint(kind):
+		return true, c.setUintVal(fval, envVal)
+	case c.isFloat(kind):
+		return true, c.setFloatVal(fval, envVal)
+	case c.isSliceField(kind):
+		return true, c.setSliceValue(fval, envVal, tag)
+	case c.isMap(kind):
+		return true, c.setMapVal(fval, envVal, tag)
+	case c.isStruct(kind):
+		return false, nil
+	default:
+		return false, errors.Errorf("unsupported %s", fval.Type().Name())
+	}
+}
+
+// setParsedVal sets the value returned by a parser registered with WithTypeParser.
+func (*Loader) setParsedVal(fval reflect.Value, envVal string, parse func(raw string) (any, error)) error {
+	v, err := parse(envVal)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !rv.Type().AssignableTo(fval.Type()) {
+		return errors.Errorf("parser returned %T, expected %s", v, fval.Type())
+	}
+
+	fval.Set(rv)
+
+	return nil
+}
+
+func (*Loader) isSliceField(kind reflect.Kind) bool {
+	return kind == reflect.Slice
+}
+
+func (c *Loader) setSliceValue(vf reflect.Value, evnVal string, tag reflect.StructTag) error {
+	var err error
+
+	// a slice of maps can be given as a JSON array, e.g. [{"a":"1"},{"b":"2"}]
+	if c.isMap(c.getDirectType(vf.Type().Elem()).Kind()) &&
+		strings.HasPrefix(strings.TrimSpace(evnVal), "[") {
+		return c.setJSONVal(vf, evnVal)
+	}
+
+	sep, elemTag := c.sliceSep(tag)
+
+	// dedup applies to the outer slice only; Lookup returns the first dedup key,
+	// so prepending hides it from nested slices
+	if _, ok := tag.Lookup("dedup"); ok {
+		elemTag = `dedup:"false" ` + elemTag
+	}
+
+	// the elements are parsed as they are split, into a slice sized from the
+	// separator count, so large lists are not copied into a []string first
+	n := countParts(evnVal, sep)
+	slice := reflect.MakeSlice(vf.Type(), n, n)
+	i := 0
+
+	err = eachPart(evnVal, sep, func(part string) error {
+		if part == "" && c.sliceTrimEmpty {
+			return nil
+		}
+
+		if _, err := c.setFieldVal(slice.Index(i), part, elemTag); err != nil {
+			return errors.Wrapf(err, "cannot set slice value")
+		}
+
+		i++
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if i == 0 {
+		return nil
+	}
+
+	slice = slice.Slice(0, i)
+
+	if dedup, _ := strconv.ParseBool(tag.Get("dedup")); dedup {
+		slice = dedupSlice(slice)
+	}
+
+	vf.Set(slice)
+
+	return nil
+}
+
+// sliceSep returns the separator of a slice value and the tag to use for its
+// elements. The sep tag lists one separator per nesting depth separated by "|",
+// e.g. sep:";|," splits "1,2;3,4" into [[1 2] [3 4]]; the elements get the
+// remaining separators. Without a separator for a depth, the array separator is used.
+func (c *Loader) sliceSep(tag reflect.StructTag) (string, reflect.StructTag) {
+	seps, ok := tag.Lookup("sep")
+	if !ok {
+		return c.arraySep, tag
+	}
+
+	sep, rest, _ := strings.Cut(seps, "|")
+	if sep == "" {
+		sep = c.arraySep
+	}
+
+	// Lookup returns the first sep key, so prepending hides the consumed separator
+	return sep, reflect.StructTag(`sep:` + strconv.Quote(rest) + ` ` + string(tag))
+}
+
+// dedupSlice returns the parsed slice without the elements equal to an earlier
+// one, keeping the first occurrence of each value. Elements are compared with
+// reflect.DeepEqual, so pointers are equal when the values they point to are.
+func dedupSlice(slice reflect.Value) reflect.Value {
+	out := reflect.MakeSlice(slice.Type(), 0, slice.Len())
+
+next:
+	for i := 0; i < slice.Len(); i++ {
+		v := slice.Index(i)
+
+		for j := 0; j < out.Len(); j++ {
+			if reflect.DeepEqual(out.Index(j).Interface(), v.Interface()) {
+				continue next
+			}
+		}
+
+		out = reflect.Append(out, v)
+	}
+
+	return out
+}
+
+// isSpaceSep reports whether sep is made of whitespace only, e.g. " " or "\t".
+// Such a separator splits around any run of whitespace, so consecutive spaces
+// and tabs do not produce empty elements.
+func isSpaceSep(sep string) bool {
+	return sep != "" && strings.TrimSpace(sep) == ""
+}
+
+// splitParts returns the parts of s split around sep, as eachPart visits them.
+func splitParts(s, sep string) []string {
+	if isSpaceSep(sep) {
+		return strings.Fields(s)
+	}
+
+	return strings.Split(s, sep)
+}
+
+// countParts returns the number of parts eachPart visits for s and sep.
+func countParts(s, sep string) int {
+	if !isSpaceSep(sep) {
+		return strings.Count(s, sep) + 1
+	}
+
+	n := 0
+	inField := false
+
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			inField = false
+		case !inField:
+			inField = true
+			n++
+		}
+	}
+
+	return n
+}
+
+// eachPart calls fn with the parts of s split around sep in order, the same
+// parts strings.Split returns, without allocating them. A whitespace separator
+// yields the parts of strings.Fields instead. It stops at the first error.
+func eachPart(s, sep string, fn func(part string) error) error {
+	if sep == "" || isSpaceSep(sep) {
+		for _, part := range splitParts(s, sep) {
+			if err := fn(part); err != nil {
+				return err
+			}
+		}
 
-	for i := 0; i < n; i++ {
-		foundField, err := c.loadToField(
-			t.Field(i),
-			v.Field(i),
-			prefix,
-		)
-		if err != nil {
-			return false, err
+		return nil
+	}
+
+	for {
+		part, rest, found := strings.Cut(s, sep)
+		if err := fn(part); err != nil {
+			return err
 		}
 
-		if foundField {
-			found = true
+		if !found {
+			return nil
 		}
+
+		s = rest
 	}
+}
 
-	return found, nil
+// removeEmpty returns parts without its empty strings.
+func removeEmpty(parts []string) []string {
+	kept := parts[:0]
+
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+
+	return kept
 }
 
-func (c *Loader) loadToField(
-	tf reflect.StructField,
+// setIndexedSliceVal discovers slice elements from keys made of the slice key
+// and the suffixes of the configured SuffixSequence (e.g. SERVER_0, SERVER_1).
+// Discovery stops at the first suffix without a value or when the sequence ends.
+// Struct elements are looked up field by field under the suffixed key.
+func (c *Loader) setIndexedSliceVal(
 	vf reflect.Value,
-	prefix []string,
-) (found bool, err error) {
-	if !vf.CanSet() {
-		return false, nil
+	envKey string,
+	sc scope,
+	tag reflect.StructTag,
+) (bool, error) {
+	sliceType := c.getDirectType(vf.Type())
+	elemType := sliceType.Elem()
+	directElemType := c.getDirectType(elemType)
+	// the element count is only known once discovery stops, so the slice starts
+	// from an estimate and grows by appending past it
+	var capacity int
+	if !c.isStruct(directElemType.Kind()) || c.isLeafType(directElemType) {
+		capacity = c.estimateIndexedLen(envKey)
 	}
 
-	t := c.getDirectType(tf.Type)
-	envKey, nPrefix := c.buildEnvKey(tf, prefix)
-	envVal, exist := os.LookupEnv(envKey)
+	slice := reflect.MakeSlice(sliceType, 0, capacity)
 
-	defer func() {
-		if p := recover(); p != nil {
-			err = errors.Errorf(
-				"cannot load to %s field (prefix=%s). panic: %v",
-				t.String(),
-				strings.Join(nPrefix, c.sep),
-				p,
-			)
+	for i := 0; ; i++ {
+		suffix, ok := c.sliceSuffixes(i)
+		if !ok {
+			break
 		}
-	}()
 
-	if exist {
-		set, err1 := c.setFieldVal(
-			vf,
-			envVal,
-		)
-		if err1 != nil {
-			return false, errors.Wrapf(err1, "cannot set field %s value", envKey)
+		elem := reflect.New(elemType).Elem()
+
+		var found bool
+
+		if c.isStruct(directElemType.Kind()) && !c.isLeafType(directElemType) {
+			elemScope := scope{
+				keys: append(sc.keys[:len(sc.keys):len(sc.keys)], suffix),
+				path: append(sc.path[:len(sc.path):len(sc.path)], strconv.Itoa(i)),
+			}
+
+			var err error
+			if found, err = c.setStructVal(elem, elemScope); err != nil {
+				return false, err
+			}
+		} else if raw, exist, err := c.lookupEnv(envKey + c.sep + suffix); err != nil {
+			return false, err
+		} else if exist {
+			raw = c.transformValue(envKey+c.sep+suffix, raw)
+
+			if _, err := c.setFieldVal(elem, raw, tag); err != nil {
+				return false, errors.Wrapf(err, "cannot set slice element %s", suffix)
+			}
+
+			found = true
 		}
 
-		if set {
-			return true, nil
+		if !found {
+			break
 		}
+
+		slice = reflect.Append(slice, elem)
+	}
+
+	if slice.Len() == 0 {
+		return false, nil
 	}
 
-	if c.isStruct(t.Kind()) {
-		return c.setStructVal(vf, nPrefix)
+	if vf.Kind() == reflect.Pointer {
+		vf.Set(reflect.New(sliceType))
+		vf = vf.Elem()
 	}
 
-	return false, nil
+	vf.Set(slice)
+
+	return true, nil
 }
 
-func (c *Loader) getFieldName(tf reflect.StructField) (name string, exactly bool) {
-	if tag, ok := tf.Tag.Lookup("env"); ok {
-		return tag, true
+// estimateIndexedLen estimates the element count of an indexed slice of scalars
+// by probing the keys of the indexes 0, 1, 3, 7, ... until one is not set, then
+// bisecting between the last index set and the first one not set. Elements past
+// a gap can make it overestimate the count, which is only used as a capacity.
+func (c *Loader) estimateIndexedLen(envKey string) int {
+	isSet := func(i int) bool {
+		suffix, ok := c.sliceSuffixes(i)
+		return ok && c.probeKey(envKey+c.sep+suffix)
 	}
 
-	// use alias name instead of field name
-	if tag, ok := tf.Tag.Lookup("alias"); ok {
-		return tag, false
+	if !isSet(0) {
+		return 0
 	}
 
-	name = tf.Name
-	if c.fieldNameTransformer != nil {
-		name = c.fieldNameTransformer(name)
+	// set is an index whose key is set, unset one whose key is not
+	set, unset := 0, 1
+	for isSet(unset) {
+		set, unset = unset, 2*unset+1
 	}
 
-	return name, false
+	for unset-set > 1 {
+		if mid := set + (unset-set)/2; isSet(mid) {
+			set = mid
+		} else {
+			unset = mid
+		}
+	}
+
+	return set + 1
 }
 
-func (c *Loader) buildEnvKey(tf reflect.StructField, parentKeys []string) (string, []string) {
-	joinKeys := func(names ...string) string {
-		arr := []string{}
+func (c *Loader) setDurationVal(vf reflect.Value, envVal string, tag reflect.StructTag) error {
+	d, err := time.ParseDuration(envVal)
+	if err != nil {
+		return err
+	}
 
-		if c.prefix != "" {
-			arr = append(arr, c.prefix)
-		}
+	if err := c.checkDurationRange(d, tag); err != nil {
+		return err
+	}
 
-		for _, name := range names {
-			// skip empty name
-			if name == "" {
-				continue
-			}
+	vf.Set(reflect.ValueOf(d).Convert(vf.Type()))
 
-			arr = append(arr, name)
-		}
+	return nil
+}
 
-		return strings.Join(arr, c.sep)
-	}
+// checkDurationRange validates d against the optional "min" and "max" tags.
+func (*Loader) checkDurationRange(d time.Duration, tag reflect.StructTag) error {
+	if raw, ok := tag.Lookup("min"); ok {
+		minimum, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.Wrapf(err, "invalid min tag %q", raw)
+		}
 
-	if tf.Anonymous {
-		return joinKeys(parentKeys...), parentKeys
+		if d < minimum {
+			return errors.Errorf("duration %s is less than min %s", d, minimum)
+		}
 	}
 
-	name, exactly := c.getFieldName(tf)
-	parentKeys = append(parentKeys, name)
+	if raw, ok := tag.Lookup("max"); ok {
+		maximum, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.Wrapf(err, "invalid max tag %q", raw)
+		}
 
-	if !exactly {
-		return joinKeys(parentKeys...), parentKeys
+		if d > maximum {
+			return errors.Errorf("duration %s is greater than max %s", d, maximum)
+		}
 	}
 
-	return name, parentKeys
+	return nil
 }
 
-func (*Loader) isTextUnmarshaler(fval reflect.Value) (encoding.TextUnmarshaler, bool) {
-	if fval.Kind() != reflect.Ptr && !fval.CanAddr() {
-		return nil, false
-	}
+// locationType is the type of *time.Location fields.
+var locationType = reflect.TypeOf((*time.Location)(nil))
 
-	if fval.Kind() != reflect.Ptr {
-		fval = fval.Addr()
-	}
+func (*Loader) isLocation(t reflect.Type) bool {
+	return t == locationType
+}
 
-	if fval.Type().NumMethod() == 0 {
-		return nil, false
+// setLocationVal loads a time zone from the IANA database, e.g. "Europe/Paris".
+// An empty value and "UTC" give time.UTC, "Local" gives time.Local.
+func (*Loader) setLocationVal(vf reflect.Value, envVal string) error {
+	loc, err := time.LoadLocation(envVal)
+	if err != nil {
+		return err
 	}
 
-	if !fval.CanInterface() {
-		return nil, false
-	}
+	vf.Set(reflect.ValueOf(loc))
 
-	if u, ok := fval.Interface().(encoding.TextUnmarshaler); ok {
-		return u, true
-	}
+	return nil
+}
+
+// timeType is the type of time.Time fields.
+var timeType = reflect.TypeOf(time.Time{})
 
-	return nil, false
+func (*Loader) isTime(t reflect.Type) bool {
+	return t == timeType
 }
 
-func (c *Loader) setFieldVal(fval reflect.Value, envVal string) (set bool, err error) {
-	if !fval.CanSet() {
-		return false, errors.Errorf("%s field is cannot be set", fval.Type().Name())
+// setTimeVal parses a time with the layout tag of the field, the layout of
+// WithTimeLayout or time.RFC3339, in this order.
+func (c *Loader) setTimeVal(vf reflect.Value, envVal string, tag reflect.StructTag) error {
+	layout := tag.Get("layout")
+	if layout == "" {
+		layout = c.timeLayout
 	}
 
-	if fval.Kind() == reflect.Pointer && fval.IsNil() {
-		fval.Set(reflect.New(fval.Type().Elem()))
+	if layout == "" {
+		layout = time.RFC3339
 	}
 
-	fval = c.getDirectVal(fval)
-	kind := fval.Kind()
-
-	if v, ok := c.isTextUnmarshaler(fval); ok {
-		return true, v.UnmarshalText([]byte(envVal))
+	ts, err := time.Parse(layout, envVal)
+	if err != nil {
+		return errors.Wrapf(err, "invalid time, expected layout %q", layout)
 	}
 
-	switch {
-	case c.isString(kind):
-		fval.SetString(envVal)
-		return true, nil
-	case c.isBool(kind):
-		return true, c.setBoolVal(fval, envVal)
-	case c.isDuration(fval):
-		return true, c.setDurationVal(fval, envVal)
-	case c.isInt(kind):
-		return true, c.setIntVal(fval, envVal)
-	case c.isUint(kind):
-		return true, c.setUintVal(fval, envVal)
-	case c.isFloat(kind):
-		return true, c.setFloatVal(fval, envVal)
-	case c.isSliceField(kind):
-		return true, c.setSliceValue(fval, envVal)
-	case c.isMap(kind):
-		return true, c.setMapVal(fval, envVal)
-	case c.isStruct(kind):
-		return false, nil
-	default:
-		return false, errors.Errorf("unsupported %s", fval.Type().Name())
-	}
-}
+	vf.Set(reflect.ValueOf(ts))
 
-func (*Loader) isSliceField(kind reflect.Kind) bool {
-	return kind == reflect.Slice
+	return nil
 }
 
-func (c *Loader) setSliceValue(vf reflect.Value, evnVal string) error {
-	var err error
+// ipNetType is the type of net.IPNet fields; net.IP is a TextUnmarshaler.
+var ipNetType = reflect.TypeOf(net.IPNet{})
 
-	parts := strings.Split(evnVal, c.arraySep)
-	if len(parts) == 0 {
+func (*Loader) isIPNet(t reflect.Type) bool {
+	return t == ipNetType
+}
+
+// setIPNetVal parses an IPv4 or IPv6 CIDR such as "10.0.0.0/8" or "fd00::/8".
+// The network is kept, so "10.1.2.3/8" loads as 10.0.0.0/8. Like net.IP, an
+// empty value gives the zero value.
+func (*Loader) setIPNetVal(vf reflect.Value, envVal string) error {
+	if envVal == "" {
+		vf.Set(reflect.Zero(ipNetType))
 		return nil
 	}
 
-	slice := reflect.MakeSlice(vf.Type(), len(parts), len(parts))
+	_, ipNet, err := net.ParseCIDR(envVal)
+	if err != nil {
+		return err
+	}
 
-	for i := range parts {
-		v := slice.Index(i)
+	vf.Set(reflect.ValueOf(*ipNet))
 
-		if _, err = c.setFieldVal(v, parts[i]); err != nil {
-			return errors.Wrapf(err, "cannot set slice value")
-		}
+	return nil
+}
+
+// isHexBytes reports whether t is a byte slice tagged format:"hex".
+func (*Loader) isHexBytes(t reflect.Type, tag reflect.StructTag) bool {
+	return tag.Get("format") == FormatHex && t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// setHexBytesVal hex-decodes the value into a byte slice, in upper or lower case.
+func (*Loader) setHexBytesVal(vf reflect.Value, envVal string) error {
+	b, err := hex.DecodeString(envVal)
+	if err != nil {
+		return errors.Wrap(err, "invalid hex value")
 	}
 
-	vf.Set(slice)
+	vf.Set(reflect.ValueOf(b).Convert(vf.Type()))
 
 	return nil
 }
 
-func (*Loader) setDurationVal(vf reflect.Value, envVal string) error {
-	d, err := time.ParseDuration(envVal)
+// urlType is the type of url.URL fields.
+var urlType = reflect.TypeOf(url.URL{})
+
+func (*Loader) isURL(t reflect.Type) bool {
+	return t == urlType
+}
+
+// setURLVal parses an absolute URL such as "https://api.example.com/v1".
+// A value without a scheme, e.g. an empty or relative one, is an error.
+func (*Loader) setURLVal(vf reflect.Value, envVal string) error {
+	u, err := url.Parse(envVal)
 	if err != nil {
 		return err
 	}
 
-	vf.Set(reflect.ValueOf(d))
+	if u.Scheme == "" {
+		return errors.Errorf("url %q has no scheme", envVal)
+	}
+
+	vf.Set(reflect.ValueOf(*u))
 
 	return nil
 }
@@ -328,12 +1366,18 @@ func (*Loader) isInt(kind reflect.Kind) bool {
 	}
 }
 
+// setIntVal parses a signed integer, rejecting values that do not fit the
+// field's size, e.g. 128 for an int8.
 func (*Loader) setIntVal(vf reflect.Value, raw string) error {
 	i, err := strconv.ParseInt(raw, 10, 64)
 	if err != nil {
 		return err
 	}
 
+	if vf.OverflowInt(i) {
+		return errors.Errorf("value %s overflows %s", raw, vf.Type())
+	}
+
 	vf.SetInt(i)
 
 	return nil
@@ -353,12 +1397,18 @@ func (*Loader) isUint(kind reflect.Kind) bool {
 	}
 }
 
+// setUintVal parses an unsigned integer, rejecting values that do not fit the
+// field's size, e.g. 65536 for a uint16.
 func (*Loader) setUintVal(vf reflect.Value, raw string) error {
 	i, err := strconv.ParseUint(raw, 10, 64)
 	if err != nil {
 		return err
 	}
 
+	if vf.OverflowUint(i) {
+		return errors.Errorf("value %s overflows %s", raw, vf.Type())
+	}
+
 	vf.SetUint(i)
 
 	return nil
@@ -373,12 +1423,17 @@ func (*Loader) isFloat(kind reflect.Kind) bool {
 	}
 }
 
+// setFloatVal parses a float, rejecting values out of the range of a float32 field.
 func (*Loader) setFloatVal(vf reflect.Value, raw string) error {
 	num, err := strconv.ParseFloat(raw, 64)
 	if err != nil {
 		return err
 	}
 
+	if vf.OverflowFloat(num) {
+		return errors.Errorf("value %s overflows %s", raw, vf.Type())
+	}
+
 	vf.SetFloat(num)
 
 	return nil
@@ -388,10 +1443,15 @@ func (*Loader) isBool(kind reflect.Kind) bool {
 	return kind == reflect.Bool
 }
 
-func (*Loader) setBoolVal(vf reflect.Value, envVal string) error {
+// setBoolVal parses the forms accepted by strconv.ParseBool, then the literals
+// registered with WithBoolLiterals regardless of their case.
+func (c *Loader) setBoolVal(vf reflect.Value, envVal string) error {
 	v, err := strconv.ParseBool(envVal)
 	if err != nil {
-		return err
+		var ok bool
+		if v, ok = c.boolLiterals[strings.ToLower(envVal)]; !ok {
+			return err
+		}
 	}
 
 	vf.SetBool(v)
@@ -411,8 +1471,129 @@ func (*Loader) isMap(kind reflect.Kind) bool {
 	return kind == reflect.Map
 }
 
-func (*Loader) setMapVal(vf reflect.Value, jsonStr string) error {
-	return json.Unmarshal([]byte(jsonStr), vf.Addr().Interface())
+func (c *Loader) setMapVal(vf reflect.Value, raw string, tag reflect.StructTag) error {
+	if tag.Get("format") == FormatKV {
+		return c.setKVMapVal(vf, raw, tag)
+	}
+
+	if c.isSet(vf.Type()) && !strings.HasPrefix(strings.TrimSpace(raw), "{") {
+		return c.setSetVal(vf, raw, tag)
+	}
+
+	return c.setJSONVal(vf, raw)
+}
+
+// isSet reports whether t is a set, a map whose values are empty structs
+// such as map[string]struct{}.
+func (*Loader) isSet(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Elem().Kind() == reflect.Struct && t.Elem().NumField() == 0
+}
+
+// setSetVal parses a separated list like a slice (honoring the sep tag) and
+// adds every element as a key of a new set; duplicate elements collapse into one key.
+func (c *Loader) setSetVal(vf reflect.Value, raw string, tag reflect.StructTag) error {
+	t := vf.Type()
+
+	sep, elemTag := c.sliceSep(tag)
+
+	parts := splitParts(raw, sep)
+	if c.sliceTrimEmpty {
+		parts = removeEmpty(parts)
+	}
+
+	set := reflect.MakeMapWithSize(t, len(parts))
+	member := reflect.New(t.Elem()).Elem()
+
+	for _, part := range parts {
+		key := reflect.New(t.Key()).Elem()
+		if _, err := c.setFieldVal(key, part, elemTag); err != nil {
+			return errors.Wrapf(err, "cannot set set element %q", part)
+		}
+
+		set.SetMapIndex(key, member)
+	}
+
+	vf.Set(set)
+
+	return nil
+}
+
+// setJSONVal decodes JSON into a new value before assigning it,
+// so maps shared with the defaults are left untouched.
+func (*Loader) setJSONVal(vf reflect.Value, raw string) error {
+	v := reflect.New(vf.Type())
+	if err := json.Unmarshal([]byte(raw), v.Interface()); err != nil {
+		return err
+	}
+
+	vf.Set(v.Elem())
+
+	return nil
+}
+
+// setKVMapVal parses a "key=value" list separated by the array separator.
+// Keys and values are parsed with the same rules as regular fields, except
+// for interface values which are inferred by inferScalar. Values are parsed
+// with the field tag, so e.g. min and max bounds apply to every duration value.
+// Spaces around keys and values are trimmed, and "a=" gives a an empty value.
+// A key without "=" is an error unless WithKVBareKeys is set, in which case
+// it gets an empty value too.
+func (c *Loader) setKVMapVal(vf reflect.Value, raw string, tag reflect.StructTag) error {
+	t := vf.Type()
+	m := reflect.MakeMap(t)
+
+	for _, pair := range splitParts(raw, c.arraySep) {
+		rawKey, rawVal, ok := strings.Cut(pair, "=")
+		rawKey, rawVal = strings.TrimSpace(rawKey), strings.TrimSpace(rawVal)
+
+		if !ok && (!c.kvBareKeys || rawKey == "") {
+			return errors.Errorf("invalid key-value pair %q", pair)
+		}
+
+		key := reflect.New(t.Key()).Elem()
+		if _, err := c.setFieldVal(key, rawKey, ""); err != nil {
+			return errors.Wrapf(err, "cannot set map key %q", rawKey)
+		}
+
+		val := reflect.New(t.Elem()).Elem()
+		if c.isAny(val.Type()) {
+			val.Set(reflect.ValueOf(inferScalar(rawVal)))
+		} else if _, err := c.setFieldVal(val, rawVal, tag); err != nil {
+			return errors.Wrapf(err, "cannot set map value of key %q", rawKey)
+		}
+
+		m.SetMapIndex(key, val)
+	}
+
+	vf.Set(m)
+
+	return nil
+}
+
+func (*Loader) isAny(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.NumMethod() == 0
+}
+
+// inferScalar guesses the type of a raw value:
+// "true"/"false" (any case) become bool, integers become int64,
+// other numbers become float64 and everything else stays a string.
+func inferScalar(raw string) any {
+	switch strings.ToLower(raw) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return i
+	}
+
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f
+	}
+
+	return raw
 }
 
 func (*Loader) getDirectType(t reflect.Type) reflect.Type {
@@ -434,20 +1615,63 @@ func (*Loader) getDirectVal(v reflect.Value) reflect.Value {
 	return realVal
 }
 
-func (c *Loader) setStructVal(vf reflect.Value, prefix []string) (found bool, err error) {
+// initStruct sets vf to the instance built by the WithStructInit hook, if any.
+// The hook may return a value or a pointer of the struct type.
+func (c *Loader) initStruct(vf reflect.Value) error {
+	if c.structInit == nil {
+		return nil
+	}
+
+	t := vf.Type()
+
+	instance, ok := c.structInit(t)
+	if !ok {
+		return nil
+	}
+
+	v := reflect.ValueOf(instance)
+	if v.Kind() == reflect.Pointer && v.Type().Elem() == t && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if !v.IsValid() || v.Type() != t {
+		return errors.Errorf("struct init returned %T, expected %s", instance, t)
+	}
+
+	vf.Set(v)
+
+	return nil
+}
+
+func (c *Loader) setStructVal(vf reflect.Value, sc scope) (found bool, err error) {
+	if vf.Kind() == reflect.Pointer && vf.Type().Elem().Kind() == reflect.Pointer {
+		return c.setPointerChainVal(vf, sc)
+	}
+
 	newVf := vf
 	needSet := false
 
-	if vf.Kind() == reflect.Pointer && vf.IsNil() {
+	switch {
+	case vf.Kind() == reflect.Pointer && vf.IsNil():
 		newVf = reflect.New(vf.Type().Elem())
 		needSet = true
-	} else {
+
+		if err := c.initStruct(newVf.Elem()); err != nil {
+			return false, err
+		}
+	case vf.Kind() != reflect.Pointer:
+		if vf.IsZero() {
+			if err := c.initStruct(vf); err != nil {
+				return false, err
+			}
+		}
+
 		newVf = vf.Addr()
 	}
 
 	found, err = c.recursiveLoadToStruct(
 		newVf.Interface(),
-		prefix,
+		sc,
 	)
 	if err != nil {
 		return false, err
@@ -459,3 +1683,23 @@ func (c *Loader) setStructVal(vf reflect.Value, prefix []string) (found bool, er
 
 	return found, nil
 }
+
+// setPointerChainVal loads a struct behind several pointer levels, e.g. **Config.
+// A nil level is allocated and kept only when a field of the struct was found,
+// so the outermost nil pointer stays nil when nothing matches.
+func (c *Loader) setPointerChainVal(vf reflect.Value, sc scope) (bool, error) {
+	if !vf.IsNil() {
+		return c.setStructVal(vf.Elem(), sc)
+	}
+
+	newVf := reflect.New(vf.Type().Elem())
+
+	found, err := c.setStructVal(newVf.Elem(), sc)
+	if err != nil || !found {
+		return false, err
+	}
+
+	vf.Set(newVf)
+
+	return true, nil
+}
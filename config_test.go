@@ -1,11 +1,19 @@
 package goconfig
 
 import (
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -201,3 +209,2240 @@ func TestErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestMapAnyConfig(t *testing.T) {
+	type Config struct {
+		JSON map[string]any
+		KV   map[string]any `format:"kv"`
+		Port map[string]int `format:"kv"`
+	}
+
+	t.Setenv("JSON", `{"name":"app","replicas":3,"debug":true,"db":{"host":"localhost"}}`)
+	t.Setenv("KV", "name=app,replicas=3,ratio=0.5,debug=TRUE,empty=")
+	t.Setenv("PORT", "http=80,https=443")
+
+	var cfg Config
+	err := Load(&cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"name":     "app",
+		"replicas": float64(3),
+		"debug":    true,
+		"db":       map[string]any{"host": "localhost"},
+	}, cfg.JSON)
+	assert.Equal(t, map[string]any{
+		"name":     "app",
+		"replicas": int64(3),
+		"ratio":    0.5,
+		"debug":    true,
+		"empty":    "",
+	}, cfg.KV)
+	assert.Equal(t, map[string]int{"http": 80, "https": 443}, cfg.Port)
+}
+
+func TestMapKVErrors(t *testing.T) {
+	type Config struct {
+		Port map[string]int `format:"kv"`
+	}
+
+	t.Setenv("PORT", "http")
+	assert.Error(t, Load(&Config{}))
+
+	t.Setenv("PORT", "http=eighty")
+	assert.Error(t, Load(&Config{}))
+}
+
+func TestMapKVEmptyValues(t *testing.T) {
+	type Config struct {
+		Flags map[string]string `format:"kv"`
+		Any   map[string]any    `format:"kv"`
+		Port  map[string]int    `format:"kv"`
+	}
+
+	t.Setenv("KVE_FLAGS", "a=,b=x, c = y ,d= ,e==")
+	t.Setenv("KVE_ANY", "a=,b=1")
+
+	var cfg Config
+
+	assert.NoError(t, New(WithPrefix("KVE")).Load(&cfg))
+	assert.Equal(t, map[string]string{"a": "", "b": "x", "c": "y", "d": "", "e": "="}, cfg.Flags)
+	assert.Equal(t, map[string]any{"a": "", "b": int64(1)}, cfg.Any)
+
+	t.Run("bare keys", func(t *testing.T) {
+		t.Setenv("KVE_FLAGS", "verbose,level=2, dry-run ")
+
+		err := New(WithPrefix("KVE")).Load(&Config{})
+		assert.ErrorContains(t, err, `invalid key-value pair "verbose"`)
+
+		var cfg Config
+
+		assert.NoError(t, New(WithPrefix("KVE"), WithKVBareKeys()).Load(&cfg))
+		assert.Equal(t, map[string]string{"verbose": "", "level": "2", "dry-run": ""}, cfg.Flags)
+	})
+
+	t.Run("empty pair", func(t *testing.T) {
+		t.Setenv("KVE_FLAGS", "a=1,,b=2")
+
+		err := New(WithPrefix("KVE"), WithKVBareKeys()).Load(&Config{})
+		assert.ErrorContains(t, err, `invalid key-value pair ""`)
+	})
+
+	t.Run("empty number", func(t *testing.T) {
+		t.Setenv("KVE_PORT", "http=")
+
+		err := New(WithPrefix("KVE")).Load(&Config{})
+		assert.ErrorContains(t, err, `cannot set map value of key "http"`)
+	})
+}
+
+func TestMapKVDurations(t *testing.T) {
+	type Config struct {
+		StageTimeouts map[string]time.Duration `format:"kv" max:"1h"`
+	}
+
+	t.Setenv("STAGE_TIMEOUTS", "read=5s,write=1m30s")
+
+	var cfg Config
+	assert.NoError(t, Load(&cfg))
+	assert.Equal(t, map[string]time.Duration{
+		"read":  5 * time.Second,
+		"write": 90 * time.Second,
+	}, cfg.StageTimeouts)
+
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("STAGE_TIMEOUTS", "read=5")
+		assert.ErrorContains(t, Load(&Config{}), "STAGE_TIMEOUTS")
+	})
+
+	t.Run("bounds apply per value", func(t *testing.T) {
+		t.Setenv("STAGE_TIMEOUTS", "read=5s,write=2h")
+		assert.ErrorContains(t, Load(&Config{}), "STAGE_TIMEOUTS")
+	})
+}
+
+func TestOnMissing(t *testing.T) {
+	type Config struct {
+		Name string `env:"APP_NAME"`
+		DB   struct {
+			Host string
+			User string
+		}
+	}
+
+	t.Setenv("DB_HOST", "db.example.com")
+
+	missing := map[string]string{}
+	loader := New(WithOnMissing(func(fieldPath, envKey string) {
+		missing[fieldPath] = envKey
+	}))
+
+	var cfg Config
+	err := loader.Load(&cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "db.example.com", cfg.DB.Host)
+	assert.Equal(t, map[string]string{
+		"Name":    "APP_NAME",
+		"DB.User": "DB_USER",
+	}, missing)
+}
+
+func TestKeyRename(t *testing.T) {
+	type Config struct {
+		CacheHost string
+		CacheUser string
+		APIToken  string `env:"API_TOKEN"`
+		Region    string
+	}
+
+	t.Setenv("REDIS_HOST", "old.example.com")
+	t.Setenv("CACHE_USER", "new-user")
+	t.Setenv("REDIS_USER", "old-user")
+	t.Setenv("TOKEN", "secret")
+	t.Setenv("REGION", "eu")
+
+	loader := New(WithKeyRename(map[string]string{
+		"REDIS_HOST": "CACHE_HOST",
+		"REDIS_USER": "CACHE_USER",
+		"TOKEN":      "API_TOKEN",
+	}))
+
+	var cfg Config
+	err := loader.Load(&cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "old.example.com", cfg.CacheHost)
+	assert.Equal(t, "new-user", cfg.CacheUser)
+	assert.Equal(t, "secret", cfg.APIToken)
+	assert.Equal(t, "eu", cfg.Region)
+}
+
+// testDecimal is a minimal fixed-point decimal storing an unscaled integer and a scale.
+type testDecimal struct {
+	unscaled int64
+	scale    int
+}
+
+func (d *testDecimal) SetString(s string) error {
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	unscaled, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	d.unscaled, d.scale = unscaled, len(fracPart)
+
+	return nil
+}
+
+func (d testDecimal) String() string {
+	s := strconv.FormatInt(d.unscaled, 10)
+	if d.scale == 0 {
+		return s
+	}
+
+	sign := ""
+	if d.unscaled < 0 {
+		sign, s = "-", s[1:]
+	}
+
+	// pad with zeros so at least one digit precedes the point
+	if len(s) <= d.scale {
+		s = strings.Repeat("0", d.scale-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+}
+
+// testTextDecimal exposes testDecimal through encoding.TextUnmarshaler.
+type testTextDecimal struct {
+	testDecimal
+}
+
+func (d *testTextDecimal) UnmarshalText(text []byte) error {
+	return d.SetString(string(text))
+}
+
+func TestDecimalConfig(t *testing.T) {
+	type Config struct {
+		Price  testTextDecimal
+		Fee    testDecimal
+		Limits []testDecimal
+	}
+
+	t.Setenv("PRICE", "19.99")
+	t.Setenv("FEE", "0.000000000000000001")
+	t.Setenv("LIMITS", "100.10,2000.05")
+
+	t.Run("text unmarshaler", func(t *testing.T) {
+		type Config struct {
+			Price  testTextDecimal
+			Fee    testTextDecimal
+			Limits []testTextDecimal
+		}
+
+		var cfg Config
+		err := Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "19.99", cfg.Price.String())
+		assert.Equal(t, testDecimal{unscaled: 1, scale: 18}, cfg.Fee.testDecimal)
+		assert.Equal(t, "0.000000000000000001", cfg.Fee.String())
+		assert.Equal(t, []testTextDecimal{{testDecimal{10010, 2}}, {testDecimal{200005, 2}}}, cfg.Limits)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		for s, d := range map[string]testDecimal{
+			"0.05":  {5, 2},
+			"-0.05": {-5, 2},
+			"-1.50": {-150, 2},
+			"42":    {42, 0},
+		} {
+			assert.Equal(t, s, d.String())
+		}
+	})
+
+	t.Run("type parser", func(t *testing.T) {
+		loader := New(WithTypeParser(reflect.TypeOf(testDecimal{}), func(raw string) (any, error) {
+			var d testDecimal
+			err := d.SetString(raw)
+			return d, err
+		}))
+
+		var cfg Config
+		err := loader.Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "19.99", cfg.Price.String())
+		assert.Equal(t, testDecimal{unscaled: 1, scale: 18}, cfg.Fee)
+		assert.Equal(t, []testDecimal{{10010, 2}, {200005, 2}}, cfg.Limits)
+	})
+
+	t.Run("type parser error", func(t *testing.T) {
+		t.Setenv("FEE", "1.2.3")
+
+		loader := New(WithTypeParser(reflect.TypeOf(testDecimal{}), func(raw string) (any, error) {
+			var d testDecimal
+			err := d.SetString(raw)
+			return d, err
+		}))
+
+		err := loader.Load(&Config{})
+		assert.Error(t, err)
+	})
+}
+
+func TestSliceSuffixes(t *testing.T) {
+	type Server struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		Mirrors []string
+		Servers []Server
+		Zones   []string
+	}
+
+	t.Setenv("MIRRORS_A", "a.example.com")
+	t.Setenv("MIRRORS_B", "b.example.com")
+	t.Setenv("MIRRORS_D", "d.example.com") // not reached, C is missing
+	t.Setenv("SERVERS_A_HOST", "one")
+	t.Setenv("SERVERS_A_PORT", "80")
+	t.Setenv("SERVERS_B_HOST", "two")
+	t.Setenv("ZONES", "eu,us")
+	t.Setenv("ZONES_A", "ignored")
+
+	t.Run("alpha", func(t *testing.T) {
+		var cfg Config
+		err := New(WithSliceSuffixes(AlphaSuffixes)).Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.Mirrors)
+		assert.Equal(t, []Server{{Host: "one", Port: 80}, {Host: "two"}}, cfg.Servers)
+		assert.Equal(t, []string{"eu", "us"}, cfg.Zones)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		var cfg Config
+		err := New(WithSliceSuffixes(ListSuffixes("B", "A"))).Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"b.example.com", "a.example.com"}, cfg.Mirrors)
+	})
+
+	t.Run("numeric", func(t *testing.T) {
+		t.Setenv("MIRRORS_0", "zero.example.com")
+		t.Setenv("MIRRORS_1", "one.example.com")
+
+		var cfg Config
+		err := New(WithSliceSuffixes(NumericSuffixes)).Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"zero.example.com", "one.example.com"}, cfg.Mirrors)
+		assert.Nil(t, cfg.Servers)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var cfg Config
+		err := Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Nil(t, cfg.Mirrors)
+	})
+}
+
+func TestDefaults(t *testing.T) {
+	type Cache struct {
+		Host string
+		TTL  time.Duration
+	}
+
+	type Config struct {
+		Name   string
+		Tags   map[string]string
+		Cache  *Cache
+		Shards []int
+	}
+
+	prototype := Config{
+		Name:   "app",
+		Tags:   map[string]string{"team": "core"},
+		Cache:  &Cache{Host: "localhost", TTL: time.Minute},
+		Shards: []int{1, 2},
+	}
+
+	t.Setenv("CACHE_HOST", "cache.example.com")
+	t.Setenv("TAGS", `{"env":"prod"}`)
+
+	var cfg Config
+	err := New(WithDefaults(prototype)).Load(&cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, Config{
+		Name:   "app",
+		Tags:   map[string]string{"env": "prod"},
+		Cache:  &Cache{Host: "cache.example.com", TTL: time.Minute},
+		Shards: []int{1, 2},
+	}, cfg)
+
+	// the prototype must not be modified by loading
+	assert.Equal(t, "localhost", prototype.Cache.Host)
+	assert.Equal(t, map[string]string{"team": "core"}, prototype.Tags)
+
+	// pointer prototypes are accepted too
+	var cfg2 Config
+	err = New(WithDefaults(&prototype)).Load(&cfg2)
+	assert.NoError(t, err)
+	assert.Equal(t, "app", cfg2.Name)
+
+	// mismatched types fail
+	err = New(WithDefaults(BasicConfig{})).Load(&cfg)
+	assert.Error(t, err)
+}
+
+func TestSliceOfMaps(t *testing.T) {
+	type Config struct {
+		TagSets []map[string]string
+		Limits  []map[string]int `format:"kv"`
+	}
+
+	t.Run("json", func(t *testing.T) {
+		t.Setenv("TAG_SETS", `[{"team":"core","env":"prod"},{"team":"web"}]`)
+
+		var cfg Config
+		err := Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []map[string]string{
+			{"team": "core", "env": "prod"},
+			{"team": "web"},
+		}, cfg.TagSets)
+	})
+
+	t.Run("indexed", func(t *testing.T) {
+		t.Setenv("TAG_SETS_0", `{"team":"core","env":"prod"}`)
+		t.Setenv("TAG_SETS_1", `{"team":"web"}`)
+		t.Setenv("LIMITS_0", "cpu=2,memory=512")
+		t.Setenv("LIMITS_1", "cpu=4")
+
+		var cfg Config
+		err := New(WithSliceSuffixes(NumericSuffixes)).Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []map[string]string{
+			{"team": "core", "env": "prod"},
+			{"team": "web"},
+		}, cfg.TagSets)
+		assert.Equal(t, []map[string]int{
+			{"cpu": 2, "memory": 512},
+			{"cpu": 4},
+		}, cfg.Limits)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		t.Setenv("TAG_SETS", `[{"team":}]`)
+
+		err := Load(&Config{})
+		assert.Error(t, err)
+	})
+}
+
+func TestValueTransformer(t *testing.T) {
+	type Config struct {
+		Secret  string
+		Retries int
+		Plain   string
+	}
+
+	t.Setenv("SECRET", "enc:s3cr3t")
+	t.Setenv("RETRIES", "enc:3")
+	t.Setenv("PLAIN", "value")
+
+	keys := []string{}
+	loader := New(WithValueTransformer(func(key, raw string) string {
+		keys = append(keys, key)
+		return strings.TrimPrefix(raw, "enc:")
+	}))
+
+	var cfg Config
+	err := loader.Load(&cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, Config{Secret: "s3cr3t", Retries: 3, Plain: "value"}, cfg)
+	assert.Equal(t, []string{"SECRET", "RETRIES", "PLAIN"}, keys)
+}
+
+func TestDurationRange(t *testing.T) {
+	type Config struct {
+		Grace       time.Duration
+		ReadTimeout time.Duration `min:"0s" max:"1h"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "negative without min",
+			env:  map[string]string{"GRACE": "-5s"},
+			want: Config{Grace: -5 * time.Second},
+		},
+		{
+			name: "zero",
+			env:  map[string]string{"READ_TIMEOUT": "0s"},
+			want: Config{},
+		},
+		{
+			name: "in range",
+			env:  map[string]string{"READ_TIMEOUT": "30m"},
+			want: Config{ReadTimeout: 30 * time.Minute},
+		},
+		{
+			name:    "negative with min",
+			env:     map[string]string{"READ_TIMEOUT": "-1s"},
+			wantErr: "duration -1s is less than min 0s",
+		},
+		{
+			name:    "over max",
+			env:     map[string]string{"READ_TIMEOUT": "90m"},
+			wantErr: "duration 1h30m0s is greater than max 1h0m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var cfg Config
+			err := Load(&cfg)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, "READ_TIMEOUT")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
+
+func TestEmbeddedStructJSON(t *testing.T) {
+	type Credentials struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+
+	type Config struct {
+		*Credentials
+		Region string
+	}
+
+	t.Run("json at parent key", func(t *testing.T) {
+		t.Setenv("SVC", `{"user":"admin","password":"secret"}`)
+		t.Setenv("SVC_USER", "ignored")
+		t.Setenv("SVC_REGION", "eu")
+
+		var cfg Config
+		err := New(WithPrefix("SVC")).Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, &Credentials{User: "admin", Password: "secret"}, cfg.Credentials)
+		assert.Equal(t, "eu", cfg.Region)
+	})
+
+	t.Run("field by field without json", func(t *testing.T) {
+		t.Setenv("SVC_USER", "admin")
+
+		var cfg Config
+		err := New(WithPrefix("SVC")).Load(&cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, &Credentials{User: "admin"}, cfg.Credentials)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		t.Setenv("SVC", `{"user":}`)
+
+		err := New(WithPrefix("SVC")).Load(&Config{})
+		assert.ErrorContains(t, err, "SVC")
+	})
+}
+
+func TestSliceLength(t *testing.T) {
+	type Config struct {
+		DNSServers []string `len:"3"`
+		Peers      []string `minlen:"1" maxlen:"2"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name: "exact",
+			env:  map[string]string{"DNS_SERVERS": "1.1.1.1,8.8.8.8,9.9.9.9", "PEERS": "a"},
+		},
+		{
+			name:    "too short",
+			env:     map[string]string{"DNS_SERVERS": "1.1.1.1,8.8.8.8"},
+			wantErr: "invalid field DNS_SERVERS value: expected length 3, got 2",
+		},
+		{
+			name:    "too long",
+			env:     map[string]string{"DNS_SERVERS": "1.1.1.1,8.8.8.8,9.9.9.9,4.4.4.4"},
+			wantErr: "invalid field DNS_SERVERS value: expected length 3, got 4",
+		},
+		{
+			name:    "above maxlen",
+			env:     map[string]string{"PEERS": "a,b,c"},
+			wantErr: "invalid field PEERS value: expected at most 2 elements, got 3",
+		},
+		{
+			name: "unset is not validated",
+			env:  map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			err := Load(&Config{})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("indexed", func(t *testing.T) {
+		t.Setenv("PEERS_0", "a")
+		t.Setenv("PEERS_1", "b")
+		t.Setenv("PEERS_2", "c")
+
+		err := New(WithSliceSuffixes(NumericSuffixes)).Load(&Config{})
+		assert.ErrorContains(t, err, "expected at most 2 elements, got 3")
+	})
+}
+
+func TestPrefixCaseFold(t *testing.T) {
+	type DB struct {
+		Name string
+	}
+
+	type Config struct {
+		ListenAddr string
+		DB         DB
+	}
+
+	t.Run("lowercase prefix", func(t *testing.T) {
+		t.Setenv("app_LISTEN_ADDR", ":8080")
+		t.Setenv("app_DB_NAME", "orders")
+
+		var cfg Config
+		err := New(WithPrefix("APP"), WithPrefixCaseFold()).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ":8080", cfg.ListenAddr)
+		assert.Equal(t, "orders", cfg.DB.Name)
+	})
+
+	t.Run("exact prefix wins", func(t *testing.T) {
+		t.Setenv("APP_LISTEN_ADDR", ":9090")
+		t.Setenv("app_LISTEN_ADDR", ":8080")
+
+		var cfg Config
+		err := New(WithPrefix("APP"), WithPrefixCaseFold()).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ":9090", cfg.ListenAddr)
+	})
+
+	t.Run("rest of key is not folded", func(t *testing.T) {
+		t.Setenv("app_listen_addr", ":8080")
+
+		var cfg Config
+		err := New(WithPrefix("APP"), WithPrefixCaseFold()).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.ListenAddr)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Setenv("app_LISTEN_ADDR", ":8080")
+
+		var cfg Config
+		err := New(WithPrefix("APP")).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.ListenAddr)
+	})
+}
+
+func TestNetipTypes(t *testing.T) {
+	type Config struct {
+		BindAddr     netip.Addr
+		AllowedNets  []netip.Prefix
+		Resolvers    []netip.Addr
+		Upstream     netip.AddrPort
+		GatewayAddr  *netip.Addr
+		InternalCIDR netip.Prefix
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Setenv("BIND_ADDR", "::1")
+		t.Setenv("ALLOWED_NETS", "10.0.0.0/8,2001:db8::/32")
+		t.Setenv("RESOLVERS", "1.1.1.1,2606:4700:4700::1111")
+		t.Setenv("UPSTREAM", "[fe80::1]:8080")
+		t.Setenv("GATEWAY_ADDR", "192.168.1.1")
+		t.Setenv("INTERNAL_CIDR", "172.16.0.0/12")
+
+		var cfg Config
+		err := Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, netip.MustParseAddr("::1"), cfg.BindAddr)
+		assert.Equal(t, []netip.Prefix{
+			netip.MustParsePrefix("10.0.0.0/8"),
+			netip.MustParsePrefix("2001:db8::/32"),
+		}, cfg.AllowedNets)
+		assert.Equal(t, []netip.Addr{
+			netip.MustParseAddr("1.1.1.1"),
+			netip.MustParseAddr("2606:4700:4700::1111"),
+		}, cfg.Resolvers)
+		assert.Equal(t, netip.MustParseAddrPort("[fe80::1]:8080"), cfg.Upstream)
+		assert.Equal(t, netip.MustParseAddr("192.168.1.1"), *cfg.GatewayAddr)
+		assert.Equal(t, netip.MustParsePrefix("172.16.0.0/12"), cfg.InternalCIDR)
+	})
+
+	t.Run("invalid addr", func(t *testing.T) {
+		t.Setenv("BIND_ADDR", "300.1.1.1")
+
+		err := Load(&Config{})
+		assert.ErrorContains(t, err, "BIND_ADDR")
+	})
+
+	t.Run("invalid prefix in slice", func(t *testing.T) {
+		t.Setenv("ALLOWED_NETS", "10.0.0.0/8,10.0.0.0/33")
+
+		err := Load(&Config{})
+		assert.ErrorContains(t, err, "ALLOWED_NETS")
+	})
+}
+
+func TestSliceTrimEmpty(t *testing.T) {
+	type Config struct {
+		Origins []string
+		Weights []int
+	}
+
+	t.Setenv("ORIGINS", "a,,b,")
+	t.Setenv("WEIGHTS", ",1,,2")
+
+	t.Run("preserve by default", func(t *testing.T) {
+		// an empty element is not a valid int
+		assert.ErrorContains(t, New().Load(&Config{}), "WEIGHTS")
+
+		t.Setenv("WEIGHTS", "1,2")
+
+		var cfg Config
+		err := New().Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "", "b", ""}, cfg.Origins)
+	})
+
+	t.Run("trim", func(t *testing.T) {
+		var cfg Config
+		err := New(WithSliceTrimEmpty(true)).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, cfg.Origins)
+		assert.Equal(t, []int{1, 2}, cfg.Weights)
+	})
+
+	t.Run("only separators", func(t *testing.T) {
+		t.Setenv("ORIGINS", ",,")
+
+		var cfg Config
+		err := New(WithSliceTrimEmpty(true)).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.Origins)
+	})
+}
+
+func TestSquashTag(t *testing.T) {
+	type Pool struct {
+		MaxConns int
+		MinConns int
+	}
+
+	type Normal struct {
+		Pool Pool
+	}
+
+	type Squashed struct {
+		Pool    Pool  `squash:"true"`
+		Backup  *Pool `squash:"true"`
+		Ignored Pool  `squash:"false"`
+	}
+
+	t.Setenv("SQ_POOL_MAX_CONNS", "10")
+	t.Setenv("SQ_MAX_CONNS", "20")
+	t.Setenv("SQ_MIN_CONNS", "2")
+	t.Setenv("SQ_IGNORED_MIN_CONNS", "1")
+
+	loader := New(WithPrefix("SQ"))
+
+	t.Run("normal nesting", func(t *testing.T) {
+		var cfg Normal
+		assert.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, Pool{MaxConns: 10}, cfg.Pool)
+	})
+
+	t.Run("squashed", func(t *testing.T) {
+		var cfg Squashed
+		assert.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, Pool{MaxConns: 20, MinConns: 2}, cfg.Pool)
+		assert.Equal(t, &Pool{MaxConns: 20, MinConns: 2}, cfg.Backup)
+		assert.Equal(t, Pool{MinConns: 1}, cfg.Ignored)
+	})
+
+	t.Run("field path keeps the field name", func(t *testing.T) {
+		var missing []string
+
+		err := New(WithPrefix("SQX"), WithOnMissing(func(fieldPath, envKey string) {
+			missing = append(missing, fieldPath+"="+envKey)
+		})).Load(&Squashed{})
+
+		assert.NoError(t, err)
+		assert.Contains(t, missing, "Pool.MaxConns=SQX_MAX_CONNS")
+		assert.Contains(t, missing, "Ignored.MaxConns=SQX_IGNORED_MAX_CONNS")
+	})
+}
+
+func TestLocation(t *testing.T) {
+	type Config struct {
+		Timezone    *time.Location
+		ReportZones []*time.Location
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	t.Run("zone", func(t *testing.T) {
+		t.Setenv("TIMEZONE", "Europe/Paris")
+		t.Setenv("REPORT_ZONES", "UTC,Europe/Paris")
+
+		var cfg Config
+		assert.NoError(t, Load(&cfg))
+		assert.Equal(t, paris.String(), cfg.Timezone.String())
+		assert.Equal(t, []*time.Location{time.UTC, cfg.Timezone}, cfg.ReportZones)
+	})
+
+	t.Run("utc and empty", func(t *testing.T) {
+		for _, v := range []string{"UTC", ""} {
+			t.Setenv("TIMEZONE", v)
+
+			var cfg Config
+			assert.NoError(t, Load(&cfg))
+			assert.Equal(t, time.UTC, cfg.Timezone)
+		}
+	})
+
+	t.Run("unset keeps nil", func(t *testing.T) {
+		var cfg Config
+		assert.NoError(t, Load(&cfg))
+		assert.Nil(t, cfg.Timezone)
+	})
+
+	t.Run("invalid zone", func(t *testing.T) {
+		t.Setenv("TIMEZONE", "Mars/Olympus_Mons")
+
+		err := Load(&Config{})
+		assert.ErrorContains(t, err, "cannot set field TIMEZONE value")
+		assert.ErrorContains(t, err, "unknown time zone Mars/Olympus_Mons")
+	})
+}
+
+func TestBoolLiterals(t *testing.T) {
+	type Config struct {
+		FeatureEnabled bool
+	}
+
+	loader := New(WithBoolLiterals([]string{"yes", "On"}, []string{"no", "off"}))
+
+	tests := []struct {
+		input    string
+		expected bool
+		wantErr  bool
+	}{
+		{input: "true", expected: true},
+		{input: "TRUE", expected: true},
+		{input: "True", expected: true},
+		{input: "F", expected: false},
+		{input: "yes", expected: true},
+		{input: "YES", expected: true},
+		{input: "on", expected: true},
+		{input: "No", expected: false},
+		{input: "OFF", expected: false},
+		{input: "tRuE", wantErr: true},
+		{input: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Setenv("FEATURE_ENABLED", tt.input)
+
+			cfg := Config{FeatureEnabled: !tt.expected}
+			err := loader.Load(&cfg)
+
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "FEATURE_ENABLED")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.FeatureEnabled)
+		})
+	}
+
+	t.Run("literals are opt-in", func(t *testing.T) {
+		t.Setenv("FEATURE_ENABLED", "yes")
+		assert.Error(t, New().Load(&Config{}))
+	})
+}
+
+func TestNestedSliceSeparators(t *testing.T) {
+	type Config struct {
+		Matrix    [][]int           `sep:";|,"`
+		Routes    [][]string        `sep:";"`
+		Cube      [][][]int         `sep:"/|;|,"`
+		Durations [][]time.Duration `sep:" |+"`
+	}
+
+	t.Setenv("MATRIX", "1,2;3,4;5")
+	t.Setenv("ROUTES", "a,b;c")
+	t.Setenv("CUBE", "1,2;3/4")
+	t.Setenv("DURATIONS", "1s+2s 3s")
+
+	var cfg Config
+	err := Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, cfg.Matrix)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, cfg.Routes)
+	assert.Equal(t, [][][]int{{{1, 2}, {3}}, {{4}}}, cfg.Cube)
+	assert.Equal(t, [][]time.Duration{{time.Second, 2 * time.Second}, {3 * time.Second}}, cfg.Durations)
+
+	t.Run("invalid inner element", func(t *testing.T) {
+		t.Setenv("MATRIX", "1,x;3")
+
+		err := Load(&Config{})
+		assert.ErrorContains(t, err, "MATRIX")
+	})
+}
+
+func TestPrefixFrom(t *testing.T) {
+	type Backend struct {
+		Addr     string
+		PoolSize int
+	}
+
+	type Config struct {
+		Store   string
+		Backend Backend  `prefixfrom:"Store"`
+		Cache   *Backend `prefixfrom:"Store"`
+	}
+
+	t.Setenv("PF_REDIS_ADDR", "localhost:6379")
+	t.Setenv("PF_REDIS_POOL_SIZE", "10")
+	t.Setenv("PF_MEMCACHED_ADDR", "localhost:11211")
+
+	loader := New(WithPrefix("PF"))
+
+	tests := []struct {
+		store    string
+		expected Backend
+	}{
+		{store: "redis", expected: Backend{Addr: "localhost:6379", PoolSize: 10}},
+		{store: "memcached", expected: Backend{Addr: "localhost:11211"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.store, func(t *testing.T) {
+			t.Setenv("PF_STORE", tt.store)
+
+			var cfg Config
+			assert.NoError(t, loader.Load(&cfg))
+			assert.Equal(t, tt.expected, cfg.Backend)
+			assert.Equal(t, &tt.expected, cfg.Cache)
+		})
+	}
+
+	t.Run("empty discriminator", func(t *testing.T) {
+		var cfg Config
+		assert.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, Backend{}, cfg.Backend)
+		assert.Nil(t, cfg.Cache)
+	})
+
+	t.Run("discriminator declared after", func(t *testing.T) {
+		type Bad struct {
+			Backend Backend `prefixfrom:"Store"`
+			Store   string
+		}
+
+		err := loader.Load(&Bad{})
+		assert.ErrorContains(t, err, "prefixfrom field Store must be declared before Backend")
+	})
+
+	t.Run("unknown discriminator", func(t *testing.T) {
+		type Bad struct {
+			Backend Backend `prefixfrom:"Kind"`
+		}
+
+		err := loader.Load(&Bad{})
+		assert.ErrorContains(t, err, "prefixfrom field Kind of Backend must be a string field")
+	})
+}
+
+func TestOneOf(t *testing.T) {
+	type Config struct {
+		Features []string `oneof:"search billing chat"`
+		LogLevel string   `oneof:"debug info warn error"`
+		Replicas []int    `oneof:"1 3 5"`
+	}
+
+	t.Run("all valid", func(t *testing.T) {
+		t.Setenv("FEATURES", "search,chat")
+		t.Setenv("LOG_LEVEL", "warn")
+		t.Setenv("REPLICAS", "3,5")
+
+		var cfg Config
+		assert.NoError(t, Load(&cfg))
+		assert.Equal(t, []string{"search", "chat"}, cfg.Features)
+		assert.Equal(t, []int{3, 5}, cfg.Replicas)
+	})
+
+	t.Run("invalid element", func(t *testing.T) {
+		t.Setenv("FEATURES", "search,payments,chat")
+
+		err := Load(&Config{})
+		assert.ErrorContains(t, err, `invalid field FEATURES value: element 1 "payments" is not one of [search billing chat]`)
+	})
+
+	t.Run("invalid int element", func(t *testing.T) {
+		t.Setenv("REPLICAS", "1,2")
+
+		err := Load(&Config{})
+		assert.ErrorContains(t, err, `invalid field REPLICAS value: element 1 "2" is not one of [1 3 5]`)
+	})
+
+	t.Run("invalid scalar", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "verbose")
+
+		err := Load(&Config{})
+		assert.ErrorContains(t, err, `invalid field LOG_LEVEL value: "verbose" is not one of [debug info warn error]`)
+	})
+}
+
+type initRegistry struct {
+	Name    string
+	entries map[string]bool
+	ready   bool
+}
+
+func newInitRegistry() *initRegistry {
+	return &initRegistry{entries: map[string]bool{}, ready: true}
+}
+
+func TestStructInit(t *testing.T) {
+	type Config struct {
+		Registry  initRegistry
+		Fallback  *initRegistry
+		Preset    initRegistry
+		Untouched *initRegistry
+	}
+
+	init := func(t reflect.Type) (any, bool) {
+		if t == reflect.TypeOf(initRegistry{}) {
+			return newInitRegistry(), true
+		}
+
+		return nil, false
+	}
+
+	t.Setenv("REGISTRY_NAME", "main")
+	t.Setenv("FALLBACK_NAME", "backup")
+
+	cfg := Config{Preset: initRegistry{Name: "preset"}}
+	err := New(WithStructInit(init)).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "main", cfg.Registry.Name)
+	assert.True(t, cfg.Registry.ready)
+	assert.NotNil(t, cfg.Registry.entries)
+	assert.Equal(t, "backup", cfg.Fallback.Name)
+	assert.True(t, cfg.Fallback.ready)
+	assert.False(t, cfg.Preset.ready, "non-zero structs are not replaced")
+	assert.Nil(t, cfg.Untouched, "nil pointers stay nil when nothing is found")
+
+	t.Run("wrong type", func(t *testing.T) {
+		err := New(WithStructInit(func(reflect.Type) (any, bool) {
+			return "oops", true
+		})).Load(&Config{})
+
+		assert.ErrorContains(t, err, "struct init returned string, expected goconfig.initRegistry")
+	})
+}
+
+// probeSource is a KVSource recording every key the Loader looks up.
+type probeSource struct {
+	memorySource
+	probed *[]string
+}
+
+func (p probeSource) Get(key string) (string, bool, error) {
+	*p.probed = append(*p.probed, key)
+	return p.memorySource.Get(key)
+}
+
+func TestLeafTag(t *testing.T) {
+	type Inner struct {
+		Name  string
+		Depth int
+	}
+
+	type Outer struct {
+		Inner Inner
+		Deep  struct{ Inner Inner }
+	}
+
+	type Config struct {
+		Plain  Outer
+		Opaque Outer  `leaf:"true"`
+		Ptr    *Outer `leaf:"true"`
+	}
+
+	load := func(values map[string]string) (Config, []string, error) {
+		var (
+			cfg    Config
+			probed []string
+		)
+
+		src := probeSource{memorySource: memorySource{values: values}, probed: &probed}
+		err := New(WithPrefix("LEAF"), WithKVSource(src, KVSourceOnly)).Load(&cfg)
+
+		return cfg, probed, err
+	}
+
+	t.Run("descendant keys are not probed", func(t *testing.T) {
+		_, probed, err := load(nil)
+
+		assert.NoError(t, err)
+		assert.Contains(t, probed, "LEAF_PLAIN_DEEP_INNER_NAME")
+		assert.Contains(t, probed, "LEAF_OPAQUE")
+		assert.Contains(t, probed, "LEAF_PTR")
+
+		for _, key := range probed {
+			assert.NotContains(t, key, "LEAF_OPAQUE_")
+			assert.NotContains(t, key, "LEAF_PTR_")
+		}
+	})
+
+	t.Run("own variable", func(t *testing.T) {
+		cfg, _, err := load(map[string]string{
+			"LEAF_OPAQUE":            `{"Inner":{"Name":"a","Depth":1}}`,
+			"LEAF_PTR":               `{"Deep":{"Inner":{"Name":"b"}}}`,
+			"LEAF_OPAQUE_INNER_NAME": "ignored",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, Inner{Name: "a", Depth: 1}, cfg.Opaque.Inner)
+		if assert.NotNil(t, cfg.Ptr) {
+			assert.Equal(t, Inner{Name: "b"}, cfg.Ptr.Deep.Inner)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, _, err := load(map[string]string{"LEAF_OPAQUE": "name=a"})
+		assert.ErrorContains(t, err, "cannot set field LEAF_OPAQUE value")
+	})
+
+	t.Run("leaf false", func(t *testing.T) {
+		type Config struct {
+			Inner Inner `leaf:"false"`
+		}
+
+		t.Setenv("LEAF_INNER_NAME", "a")
+
+		var cfg Config
+		assert.NoError(t, New(WithPrefix("LEAF")).Load(&cfg))
+		assert.Equal(t, "a", cfg.Inner.Name)
+	})
+}
+
+func TestEnabledBy(t *testing.T) {
+	type TLS struct {
+		Cert    string `group:"tls"`
+		Key     string `group:"tls"`
+		Version string `oneof:"1.2 1.3"`
+	}
+
+	type Config struct {
+		TLSEnabled bool
+		TLS        TLS  `enabledby:"TLSEnabled"`
+		Client     *TLS `enabledby:"TLSEnabled"`
+	}
+
+	// a partial group and an invalid version, only reported when enabled
+	t.Setenv("EB_TLS_CERT", "server.crt")
+	t.Setenv("EB_TLS_VERSION", "1.0")
+	t.Setenv("EB_CLIENT_CERT", "client.crt")
+	t.Setenv("EB_CLIENT_KEY", "client.key")
+	t.Setenv("EB_CLIENT_VERSION", "1.3")
+
+	loader := New(WithPrefix("EB"), WithRequiredGroups())
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("EB_TLS_ENABLED", "false")
+
+		var missing []string
+
+		var cfg Config
+		err := New(WithPrefix("EB"), WithRequiredGroups(), WithOnMissing(func(_, envKey string) {
+			missing = append(missing, envKey)
+		})).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{}, cfg)
+		assert.Empty(t, missing)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		var cfg Config
+		assert.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, Config{}, cfg)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Setenv("EB_TLS_ENABLED", "true")
+
+		var cfg Config
+		err := loader.Load(&cfg)
+
+		assert.ErrorContains(t, err, `invalid field EB_TLS_VERSION value: "1.0" is not one of [1.2 1.3]`)
+
+		t.Setenv("EB_TLS_VERSION", "1.2")
+
+		err = loader.Load(&cfg)
+
+		assert.ErrorIs(t, err, ErrPartialGroup)
+
+		t.Setenv("EB_TLS_KEY", "server.key")
+
+		cfg = Config{}
+		assert.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, Config{
+			TLSEnabled: true,
+			TLS:        TLS{Cert: "server.crt", Key: "server.key", Version: "1.2"},
+			Client:     &TLS{Cert: "client.crt", Key: "client.key", Version: "1.3"},
+		}, cfg)
+	})
+
+	t.Run("bool declared after", func(t *testing.T) {
+		type Bad struct {
+			TLS     TLS `enabledby:"Enabled"`
+			Enabled bool
+		}
+
+		err := loader.Load(&Bad{})
+		assert.ErrorContains(t, err, "enabledby field Enabled must be declared before TLS")
+	})
+
+	t.Run("not a bool", func(t *testing.T) {
+		type Bad struct {
+			Enabled string
+			TLS     TLS `enabledby:"Enabled"`
+		}
+
+		err := loader.Load(&Bad{})
+		assert.ErrorContains(t, err, "enabledby field Enabled of TLS must be a bool field")
+	})
+}
+
+func BenchmarkLargeSlice(b *testing.B) {
+	type Config struct {
+		Allow []string
+		Ports []int
+	}
+
+	words := make([]string, 50000)
+	ports := make([]string, len(words))
+
+	for i := range words {
+		words[i] = "host-" + strconv.Itoa(i) + ".example.com"
+		ports[i] = strconv.Itoa(i % 65536)
+	}
+
+	b.Setenv("BENCH_ALLOW", strings.Join(words, ","))
+	b.Setenv("BENCH_PORTS", strings.Join(ports, ","))
+
+	loader := New(WithPrefix("BENCH"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var cfg Config
+		if err := loader.Load(&cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIndexedSlice(b *testing.B) {
+	type Config struct {
+		Hosts []string
+	}
+
+	const n = 2000
+
+	for i := 0; i < n; i++ {
+		b.Setenv("BENCHIDX_HOSTS_"+strconv.Itoa(i), "host-"+strconv.Itoa(i)+".example.com")
+	}
+
+	loader := New(WithPrefix("BENCHIDX"), WithSliceSuffixes(NumericSuffixes))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var cfg Config
+		if err := loader.Load(&cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestIndexedSliceCapacity(t *testing.T) {
+	type Config struct {
+		Hosts []string
+		Zones []string
+	}
+
+	load := func(t *testing.T, opts ...Option) (Config, error) {
+		t.Helper()
+
+		var cfg Config
+		err := New(append([]Option{WithPrefix("CAPEST"), WithSliceSuffixes(NumericSuffixes)}, opts...)...).Load(&cfg)
+
+		return cfg, err
+	}
+
+	for _, n := range []int{1, 2, 3, 5, 8, 13, 100} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			hosts := make([]string, n)
+			for i := range hosts {
+				hosts[i] = "host-" + strconv.Itoa(i)
+				t.Setenv("CAPEST_HOSTS_"+strconv.Itoa(i), hosts[i])
+			}
+
+			cfg, err := load(t)
+
+			assert.NoError(t, err)
+			assert.Equal(t, hosts, cfg.Hosts)
+			assert.Equal(t, n, cap(cfg.Hosts), "sized from the probed keys")
+		})
+	}
+
+	t.Run("gap", func(t *testing.T) {
+		t.Setenv("CAPEST_ZONES_0", "a")
+		t.Setenv("CAPEST_ZONES_1", "b")
+		t.Setenv("CAPEST_ZONES_3", "d")
+
+		cfg, err := load(t)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, cfg.Zones)
+
+		// probing past the gap does not count as reading the key
+		_, err = load(t, WithStrictPrefix())
+		assert.EqualError(t, err, "CAPEST_ZONES_3: unknown variable")
+	})
+}
+
+func TestEachPart(t *testing.T) {
+	for _, tt := range []struct{ s, sep string }{
+		{"", ","},
+		{"a", ","},
+		{"a,b,,c", ","},
+		{",a,", ","},
+		{"a::b:c", "::"},
+		{"abc", ""},
+		{"", ""},
+	} {
+		var parts []string
+
+		err := eachPart(tt.s, tt.sep, func(part string) error {
+			parts = append(parts, part)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Split(tt.s, tt.sep), append([]string{}, parts...), "%q split by %q", tt.s, tt.sep)
+	}
+}
+
+func TestWhitespaceArraySeparator(t *testing.T) {
+	type Config struct {
+		Args  []string
+		Ports []int
+		Tags  map[string]struct{}
+		Paths []string `sep:":"`
+		Empty []string
+	}
+
+	t.Setenv("WSSEP_ARGS", "  --a   --b\t\t--c\n")
+	t.Setenv("WSSEP_PORTS", "80 \t 443")
+	t.Setenv("WSSEP_TAGS", "x  y\tx")
+	t.Setenv("WSSEP_PATHS", "/bin:/usr/local/bin")
+	t.Setenv("WSSEP_EMPTY", " \t ")
+
+	var cfg Config
+
+	err := Load(&cfg, WithPrefix("WSSEP"), WithArraySeparator(" "))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"--a", "--b", "--c"}, cfg.Args)
+	assert.Equal(t, []int{80, 443}, cfg.Ports)
+	assert.Equal(t, map[string]struct{}{"x": {}, "y": {}}, cfg.Tags)
+	assert.Equal(t, []string{"/bin", "/usr/local/bin"}, cfg.Paths)
+	assert.Nil(t, cfg.Empty)
+
+	for _, tt := range []struct{ s, sep string }{
+		{"a  b\tc", " "},
+		{"\ta\t\tb ", "\t"},
+		{"", " "},
+	} {
+		var parts []string
+
+		err := eachPart(tt.s, tt.sep, func(part string) error {
+			parts = append(parts, part)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Fields(tt.s), append([]string{}, parts...), "%q split by %q", tt.s, tt.sep)
+		assert.Equal(t, len(parts), countParts(tt.s, tt.sep))
+	}
+}
+
+func TestStructTagAliasSeparator(t *testing.T) {
+	type DB struct {
+		Host string `alias:"HOST,ADDR"`
+	}
+
+	type Config struct {
+		Host    string `alias:"DB_HOST, DATABASE_HOST"`
+		Port    int    `alias:"DB_PORT,DATABASE_PORT,PORT"`
+		User    string `alias:"DB_USER,DATABASE_USER"`
+		Primary DB     `alias:"PRIMARY,MAIN"`
+	}
+
+	t.Setenv("AS_DATABASE_HOST", "db.internal")
+	t.Setenv("AS_PORT", "5432")
+	t.Setenv("AS_DB_USER", "admin")
+	t.Setenv("AS_DATABASE_USER", "ignored")
+	t.Setenv("AS_PRIMARY_ADDR", "primary.internal")
+	t.Setenv("AS_MAIN_HOST", "ignored")
+
+	var cfg Config
+	err := New(WithPrefix("AS"), WithStructTagAliasSeparator(",")).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "db.internal", cfg.Host, "the second alias matches")
+	assert.Equal(t, 5432, cfg.Port, "aliases are tried in order")
+	assert.Equal(t, "admin", cfg.User, "the first alias wins")
+	assert.Equal(t, "primary.internal", cfg.Primary.Host, "nested keys use the first alias")
+
+	t.Run("errors name the matched key", func(t *testing.T) {
+		t.Setenv("AS_PORT", "http")
+
+		err := New(WithPrefix("AS"), WithStructTagAliasSeparator(",")).Load(&Config{})
+		assert.ErrorContains(t, err, "cannot set field AS_PORT value")
+	})
+
+	t.Run("without separator", func(t *testing.T) {
+		type Config struct {
+			Host string `alias:"DB_HOST,DATABASE_HOST"`
+		}
+
+		t.Setenv("AS_DB_HOST,DATABASE_HOST", "literal")
+
+		var cfg Config
+		assert.NoError(t, New(WithPrefix("AS")).Load(&cfg))
+		assert.Equal(t, "literal", cfg.Host)
+	})
+}
+
+func TestEnvFallbackKeys(t *testing.T) {
+	type Config struct {
+		Port    int    `env:"FBK_PORT,FBK_SERVER_PORT,FBK_HTTP_PORT"`
+		Host    string `env:"FBK_HOST, FBK_SERVER_HOST"`
+		User    string `env:"FBK_USER,FBK_DB_USER"`
+		Name    string `env:"FBK_NAME"`
+		Missing string `env:"FBK_MISSING,FBK_OLD_MISSING" required:"true"`
+	}
+
+	t.Setenv("FBK_HTTP_PORT", "8080")
+	t.Setenv("FBK_SERVER_HOST", "api.internal")
+	t.Setenv("FBK_USER", "admin")
+	t.Setenv("FBK_DB_USER", "ignored")
+	t.Setenv("FBK_NAME", "app")
+
+	var cfg Config
+	err := New(WithPrefix("IGNORED")).Load(&cfg)
+
+	assert.ErrorIs(t, err, ErrMissingRequired)
+	assert.ErrorContains(t, err, "FBK_MISSING")
+	assert.Equal(t, 8080, cfg.Port, "keys are tried in order")
+	assert.Equal(t, "api.internal", cfg.Host, "spaces are trimmed")
+	assert.Equal(t, "admin", cfg.User, "the first key wins")
+	assert.Equal(t, "app", cfg.Name)
+
+	t.Run("errors name the matched key", func(t *testing.T) {
+		t.Setenv("FBK_MISSING", "set")
+		t.Setenv("FBK_SERVER_PORT", "http")
+
+		err := New().Load(&Config{})
+		assert.ErrorContains(t, err, "cannot set field FBK_SERVER_PORT value")
+	})
+
+	t.Run("nested keys use the first key", func(t *testing.T) {
+		type Config struct {
+			DB struct {
+				Host string
+			} `env:"FBK_DB,FBK_DATABASE"`
+		}
+
+		t.Setenv("FBK_DB_HOST", "db.internal")
+		t.Setenv("FBK_DATABASE_HOST", "ignored")
+
+		var cfg Config
+		assert.NoError(t, New().Load(&cfg))
+		assert.Equal(t, "db.internal", cfg.DB.Host)
+	})
+}
+
+func TestBigRat(t *testing.T) {
+	type Config struct {
+		Ratio  *big.Rat
+		Rate   big.Rat
+		Shares []*big.Rat
+		Unset  *big.Rat
+	}
+
+	t.Setenv("BR_RATIO", "3/4")
+	t.Setenv("BR_RATE", "0.1")
+	t.Setenv("BR_SHARES", "1/3,2/3,-1.5")
+
+	var cfg Config
+	err := New(WithPrefix("BR")).Load(&cfg)
+
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, cfg.Ratio) {
+		assert.Equal(t, "3/4", cfg.Ratio.String())
+	}
+
+	assert.Equal(t, "1/10", cfg.Rate.String(), "decimals are exact")
+
+	if assert.Len(t, cfg.Shares, 3) {
+		assert.Equal(t, "1/3", cfg.Shares[0].RatString())
+		assert.Equal(t, "2/3", cfg.Shares[1].RatString())
+		assert.Equal(t, "-3/2", cfg.Shares[2].RatString())
+	}
+
+	assert.Nil(t, cfg.Unset)
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, raw := range []string{"3/0", "three quarters", "1/2/3"} {
+			t.Setenv("BR_RATIO", raw)
+
+			err := New(WithPrefix("BR")).Load(&Config{})
+			assert.ErrorContains(t, err, "cannot set field BR_RATIO value", raw)
+		}
+	})
+}
+
+// testLowerKey is a string map key normalized by its UnmarshalText method.
+type testLowerKey string
+
+func (k *testLowerKey) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("empty key")
+	}
+
+	*k = testLowerKey(strings.ToLower(string(text)))
+
+	return nil
+}
+
+// testEndpoint is a struct map key parsed from "host:port".
+type testEndpoint struct {
+	host string
+	port int
+}
+
+func (e *testEndpoint) UnmarshalText(text []byte) error {
+	host, port, ok := strings.Cut(string(text), ":")
+	if !ok {
+		return errors.Errorf("missing port in %q", text)
+	}
+
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return err
+	}
+
+	e.host, e.port = host, p
+
+	return nil
+}
+
+func TestTextUnmarshalerMapKeys(t *testing.T) {
+	type Config struct {
+		Owners   map[testLowerKey]string `format:"kv"`
+		Weights  map[testEndpoint]int    `format:"kv"`
+		Known    map[testEndpoint]struct{}
+		Timeouts map[testLowerKey]time.Duration `format:"kv"`
+		FromJSON map[testLowerKey]int
+	}
+
+	t.Setenv("MK_OWNERS", "Payments=alice,SEARCH=bob")
+	t.Setenv("MK_WEIGHTS", "a.example:80=3,b.example:8080=1")
+	t.Setenv("MK_KNOWN", "a.example:80,a.example:80")
+	t.Setenv("MK_TIMEOUTS", "Read=5s")
+	t.Setenv("MK_FROM_JSON", `{"A":1}`)
+
+	var cfg Config
+	err := New(WithPrefix("MK")).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[testLowerKey]string{"payments": "alice", "search": "bob"}, cfg.Owners)
+	assert.Equal(t, map[testEndpoint]int{{"a.example", 80}: 3, {"b.example", 8080}: 1}, cfg.Weights)
+	assert.Equal(t, map[testEndpoint]struct{}{{"a.example", 80}: {}}, cfg.Known)
+	assert.Equal(t, map[testLowerKey]time.Duration{"read": 5 * time.Second}, cfg.Timeouts)
+	assert.Equal(t, map[testLowerKey]int{"a": 1}, cfg.FromJSON)
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Setenv("MK_WEIGHTS", "a.example=3")
+
+		err := New(WithPrefix("MK")).Load(&Config{})
+		assert.ErrorContains(t, err, `cannot set map key "a.example": missing port in "a.example"`)
+	})
+}
+
+func TestDedupTag(t *testing.T) {
+	type Config struct {
+		Allow   []string   `dedup:"true"`
+		Ports   []int      `dedup:"true"`
+		Keep    []string   `dedup:"false"`
+		Ptrs    []*int     `dedup:"true"`
+		Nested  [][]string `dedup:"true" sep:";|,"`
+		Matrix  [][]int    `dedup:"true" sep:";|,"`
+		Bounded []string   `dedup:"true" maxlen:"2"`
+	}
+
+	t.Setenv("DD_ALLOW", "b,a,b,c,a")
+	t.Setenv("DD_PORTS", "80,443,080,80")
+	t.Setenv("DD_KEEP", "x,x")
+	t.Setenv("DD_PTRS", "1,2,1")
+	t.Setenv("DD_NESTED", "a,a,b;b;a,b")
+	t.Setenv("DD_MATRIX", "1,1;1,1;2,3")
+	t.Setenv("DD_BOUNDED", "a,b,a,b")
+
+	var cfg Config
+	err := New(WithPrefix("DD")).Load(&cfg)
+
+	one, two := 1, 2
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, cfg.Allow)
+	assert.Equal(t, []int{80, 443}, cfg.Ports, "values are compared after parsing")
+	assert.Equal(t, []string{"x", "x"}, cfg.Keep)
+	assert.Equal(t, []*int{&one, &two}, cfg.Ptrs)
+	assert.Equal(t, [][]string{{"a", "a", "b"}, {"b"}, {"a", "b"}}, cfg.Nested, "only the outer slice is deduplicated")
+	assert.Equal(t, [][]int{{1, 1}, {2, 3}}, cfg.Matrix)
+	assert.Equal(t, []string{"a", "b"}, cfg.Bounded, "length is checked after deduplication")
+}
+
+func TestSetField(t *testing.T) {
+	type Config struct {
+		Names  map[string]struct{}
+		Ports  map[int]struct{}
+		Semi   map[string]struct{} `sep:";"`
+		Object map[string]struct{}
+	}
+
+	t.Setenv("SETF_NAMES", "b,a,b")
+	t.Setenv("SETF_PORTS", "80,443,080,80")
+	t.Setenv("SETF_SEMI", "a,b;c")
+	t.Setenv("SETF_OBJECT", `{"x":{}}`)
+
+	var cfg Config
+	err := New(WithPrefix("SETF")).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"a": {}, "b": {}}, cfg.Names)
+	assert.Equal(t, map[int]struct{}{80: {}, 443: {}}, cfg.Ports, "duplicates are compared after parsing")
+	assert.Equal(t, map[string]struct{}{"a,b": {}, "c": {}}, cfg.Semi)
+	assert.Equal(t, map[string]struct{}{"x": {}}, cfg.Object, "a JSON object is still decoded as JSON")
+
+	t.Setenv("SETF_PORTS", "80,http")
+
+	err = New(WithPrefix("SETF")).Load(&Config{})
+	assert.ErrorContains(t, err, `cannot set set element "http"`)
+}
+
+func TestNestedPointers(t *testing.T) {
+	type Leaf struct {
+		Name string
+	}
+
+	type Middle struct {
+		Inner *Leaf
+		Other *Leaf
+	}
+
+	type Config struct {
+		Two     **Leaf
+		Three   ***Leaf
+		Chain   *Middle
+		Missing **Leaf
+		Port    **int
+	}
+
+	t.Setenv("NP_TWO_NAME", "two")
+	t.Setenv("NP_THREE_NAME", "three")
+	t.Setenv("NP_CHAIN_INNER_NAME", "inner")
+	t.Setenv("NP_PORT", "8080")
+
+	var cfg Config
+	err := New(WithPrefix("NP")).Load(&cfg)
+
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, cfg.Two) && assert.NotNil(t, *cfg.Two) {
+		assert.Equal(t, "two", (*cfg.Two).Name)
+	}
+
+	if assert.NotNil(t, cfg.Three) && assert.NotNil(t, *cfg.Three) && assert.NotNil(t, **cfg.Three) {
+		assert.Equal(t, "three", (**cfg.Three).Name)
+	}
+
+	if assert.NotNil(t, cfg.Chain) && assert.NotNil(t, cfg.Chain.Inner) {
+		assert.Equal(t, "inner", cfg.Chain.Inner.Name)
+		assert.Nil(t, cfg.Chain.Other, "unmatched descendants stay nil")
+	}
+
+	assert.Nil(t, cfg.Missing, "unmatched pointer chains stay nil")
+
+	if assert.NotNil(t, cfg.Port) && assert.NotNil(t, *cfg.Port) {
+		assert.Equal(t, 8080, **cfg.Port)
+	}
+
+	t.Run("allocated levels are reused", func(t *testing.T) {
+		leaf := &Leaf{Name: "kept"}
+		cfg := Config{Two: &leaf, Missing: new(*Leaf)}
+
+		assert.NoError(t, New(WithPrefix("NP")).Load(&cfg))
+		assert.Same(t, leaf, *cfg.Two)
+		assert.Equal(t, "two", leaf.Name)
+		assert.Nil(t, *cfg.Missing)
+	})
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	type Config struct {
+		I8  int8
+		I16 int16
+		I32 int32
+		I64 int64
+		U8  uint8
+		U16 uint16
+		U32 uint32
+		F32 float32
+	}
+
+	t.Run("boundaries", func(t *testing.T) {
+		env := map[string]string{
+			"OF_I8":  "-128",
+			"OF_I16": "32767",
+			"OF_I32": "-2147483648",
+			"OF_I64": "9223372036854775807",
+			"OF_U8":  "255",
+			"OF_U16": "65535",
+			"OF_U32": "4294967295",
+			"OF_F32": "3.4e38",
+		}
+		for k, v := range env {
+			t.Setenv(k, v)
+		}
+
+		var cfg Config
+		assert.NoError(t, New(WithPrefix("OF")).Load(&cfg))
+		assert.Equal(t, Config{
+			I8: math.MinInt8, I16: math.MaxInt16, I32: math.MinInt32, I64: math.MaxInt64,
+			U8: math.MaxUint8, U16: math.MaxUint16, U32: math.MaxUint32, F32: 3.4e38,
+		}, cfg)
+	})
+
+	tests := []struct {
+		key     string
+		value   string
+		wantErr string
+	}{
+		{key: "OF_I8", value: "128", wantErr: "value 128 overflows int8"},
+		{key: "OF_I8", value: "-129", wantErr: "value -129 overflows int8"},
+		{key: "OF_I16", value: "32768", wantErr: "value 32768 overflows int16"},
+		{key: "OF_I32", value: "2147483648", wantErr: "value 2147483648 overflows int32"},
+		{key: "OF_I64", value: "9223372036854775808", wantErr: "value out of range"},
+		{key: "OF_U8", value: "256", wantErr: "value 256 overflows uint8"},
+		{key: "OF_U16", value: "65536", wantErr: "value 65536 overflows uint16"},
+		{key: "OF_U32", value: "4294967296", wantErr: "value 4294967296 overflows uint32"},
+		{key: "OF_F32", value: "3.5e38", wantErr: "value 3.5e38 overflows float32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+
+			err := New(WithPrefix("OF")).Load(&Config{})
+			assert.ErrorContains(t, err, "cannot set field "+tt.key+" value")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("slice elements", func(t *testing.T) {
+		type Ports struct {
+			Ports []uint16
+		}
+
+		t.Setenv("OF_PORTS", "80,70000")
+
+		err := New(WithPrefix("OF")).Load(&Ports{})
+		assert.ErrorContains(t, err, "value 70000 overflows uint16")
+	})
+}
+
+func TestKeyDepthLimit(t *testing.T) {
+	type Pool struct {
+		Size int
+	}
+
+	type DB struct {
+		Host string
+		Pool Pool
+	}
+
+	type Embedded struct {
+		Region string
+	}
+
+	type Config struct {
+		Embedded
+		Name string
+		DB   DB
+	}
+
+	keys := func(t *testing.T, limit int) []string {
+		t.Helper()
+
+		var probed []string
+
+		src := probeSource{memorySource: memorySource{}, probed: &probed}
+		err := New(WithKVSource(src, KVSourceOnly), WithKeyDepthLimit(limit)).Load(&Config{})
+		assert.NoError(t, err)
+
+		return probed
+	}
+
+	// the embedded struct is looked up under the empty key of the root for its JSON form
+	tests := []struct {
+		limit    int
+		expected []string
+	}{
+		{limit: 0, expected: []string{"", "REGION", "NAME", "DB", "DB_HOST", "DB_POOL", "DB_POOL_SIZE"}},
+		{limit: 3, expected: []string{"", "REGION", "NAME", "DB", "DB_HOST", "DB_POOL", "DB_POOL_SIZE"}},
+		{limit: 2, expected: []string{"", "REGION", "NAME", "DB", "DB_HOST", "DB_POOL"}},
+		{limit: 1, expected: []string{"", "REGION", "NAME", "DB"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.limit), func(t *testing.T) {
+			assert.Equal(t, tt.expected, keys(t, tt.limit))
+		})
+	}
+
+	t.Run("structs at the limit are read as JSON", func(t *testing.T) {
+		t.Setenv("KDL_DB", `{"Host":"db","Pool":{"Size":5}}`)
+		t.Setenv("KDL_DB_HOST", "ignored")
+
+		var cfg Config
+		assert.NoError(t, New(WithPrefix("KDL"), WithKeyDepthLimit(1)).Load(&cfg))
+		assert.Equal(t, DB{Host: "db", Pool: Pool{Size: 5}}, cfg.DB)
+	})
+
+	t.Run("check", func(t *testing.T) {
+		type Deep struct {
+			Inner struct{ Ch chan int }
+		}
+
+		type Config struct {
+			Deep Deep
+		}
+
+		assert.ErrorIs(t, New().Check(&Config{}), ErrUnsupportedType)
+		assert.NoError(t, New(WithKeyDepthLimit(1)).Check(&Config{}))
+	})
+}
+
+func TestIPFields(t *testing.T) {
+	type Config struct {
+		Bind    net.IP `env:"IPF_BIND_ADDR"`
+		Peer    *net.IP
+		Subnet  net.IPNet
+		Allowed []net.IPNet
+		Proxy   *net.IPNet
+		DNS     []net.IP
+	}
+
+	t.Setenv("IPF_BIND_ADDR", "127.0.0.1")
+	t.Setenv("IPF_PEER", "2001:db8::1")
+	t.Setenv("IPF_SUBNET", "10.1.2.3/8")
+	t.Setenv("IPF_ALLOWED", "192.168.0.0/16,fd00::/8")
+	t.Setenv("IPF_PROXY", "172.16.0.0/12")
+	t.Setenv("IPF_DNS", "1.1.1.1,::1")
+
+	var cfg Config
+	err := New(WithPrefix("IPF")).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.True(t, net.IPv4(127, 0, 0, 1).Equal(cfg.Bind))
+
+	if assert.NotNil(t, cfg.Peer) {
+		assert.Equal(t, "2001:db8::1", cfg.Peer.String())
+	}
+
+	assert.Equal(t, "10.0.0.0/8", cfg.Subnet.String(), "the network is kept")
+
+	if assert.Len(t, cfg.Allowed, 2) {
+		assert.Equal(t, "192.168.0.0/16", cfg.Allowed[0].String())
+		assert.Equal(t, "fd00::/8", cfg.Allowed[1].String())
+	}
+
+	if assert.NotNil(t, cfg.Proxy) {
+		assert.True(t, cfg.Proxy.Contains(net.ParseIP("172.20.0.1")))
+	}
+
+	assert.Equal(t, []net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("::1")}, cfg.DNS)
+
+	t.Run("invalid", func(t *testing.T) {
+		for key, raw := range map[string]string{
+			"IPF_BIND_ADDR": "localhost",
+			"IPF_PEER":      "2001:db8::zz",
+			"IPF_SUBNET":    "10.0.0.1",
+			"IPF_ALLOWED":   "192.168.0.0/33",
+		} {
+			t.Setenv(key, raw)
+
+			err := New(WithPrefix("IPF")).Load(&Config{})
+			assert.ErrorContains(t, err, "cannot set field "+key+" value", raw)
+			assert.ErrorContains(t, err, raw)
+
+			t.Setenv(key, "")
+		}
+	})
+}
+
+func TestTimeFields(t *testing.T) {
+	type Config struct {
+		StartAt  time.Time `env:"TF_START_AT"`
+		Birthday time.Time `layout:"2006-01-02"`
+		EndAt    *time.Time
+		Holidays []time.Time `layout:"01/02"`
+	}
+
+	t.Setenv("TF_START_AT", "2026-03-01T09:30:00+02:00")
+	t.Setenv("TF_BIRTHDAY", "1990-12-24")
+	t.Setenv("TF_END_AT", "2026-03-01T18:00:00.5Z")
+	t.Setenv("TF_HOLIDAYS", "12/25,01/01")
+
+	var cfg Config
+	err := New(WithPrefix("TF")).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.True(t, cfg.StartAt.Equal(time.Date(2026, 3, 1, 7, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(1990, 12, 24, 0, 0, 0, 0, time.UTC), cfg.Birthday)
+
+	if assert.NotNil(t, cfg.EndAt) {
+		assert.Equal(t, time.Date(2026, 3, 1, 18, 0, 0, 5e8, time.UTC), *cfg.EndAt)
+	}
+
+	assert.Equal(t, []time.Time{
+		time.Date(0, 12, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, cfg.Holidays)
+
+	t.Run("global layout", func(t *testing.T) {
+		t.Setenv("TF_START_AT", "2026-03-01 09:30:00")
+		t.Setenv("TF_END_AT", "2026-03-02 10:00:00")
+
+		var cfg Config
+		err := New(WithPrefix("TF"), WithTimeLayout(time.DateTime)).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC), cfg.StartAt)
+		assert.Equal(t, time.Date(1990, 12, 24, 0, 0, 0, 0, time.UTC), cfg.Birthday, "the tag wins")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		t.Setenv("TF_BIRTHDAY", "24/12/1990")
+
+		err := New(WithPrefix("TF")).Load(&Config{})
+		assert.ErrorContains(t, err, "cannot set field TF_BIRTHDAY value")
+		assert.ErrorContains(t, err, `expected layout "2006-01-02"`)
+		assert.ErrorContains(t, err, "24/12/1990")
+	})
+}
+
+func TestTextUnmarshalerSliceElements(t *testing.T) {
+	type Config struct {
+		Endpoints    []testEndpoint
+		EndpointPtrs []*testEndpoint
+		Owners       []testLowerKey   `dedup:"true"`
+		Groups       [][]testLowerKey `sep:";|,"`
+		Backends     []testEndpoint
+	}
+
+	t.Setenv("TUS_ENDPOINTS", "db:5432,cache:6379")
+	t.Setenv("TUS_ENDPOINT_PTRS", "api:443")
+	t.Setenv("TUS_OWNERS", "Alice,BOB,alice")
+	t.Setenv("TUS_GROUPS", "A,b;C")
+	t.Setenv("TUS_BACKENDS_0", "web-1:80")
+	t.Setenv("TUS_BACKENDS_1", "web-2:80")
+
+	var cfg Config
+	err := New(WithPrefix("TUS"), WithSliceSuffixes(NumericSuffixes)).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []testEndpoint{{"db", 5432}, {"cache", 6379}}, cfg.Endpoints)
+	assert.Equal(t, []*testEndpoint{{"api", 443}}, cfg.EndpointPtrs)
+	assert.Equal(t, []testLowerKey{"alice", "bob"}, cfg.Owners)
+	assert.Equal(t, [][]testLowerKey{{"a", "b"}, {"c"}}, cfg.Groups)
+	assert.Equal(t, []testEndpoint{{"web-1", 80}, {"web-2", 80}}, cfg.Backends)
+
+	t.Run("element error", func(t *testing.T) {
+		t.Setenv("TUS_ENDPOINTS", "db:5432,cache")
+
+		err := New(WithPrefix("TUS")).Load(&Config{})
+		assert.ErrorContains(t, err, `missing port in "cache"`)
+	})
+}
+
+func TestLoadPrefixed(t *testing.T) {
+	type Cache struct {
+		Size int
+		TTL  time.Duration
+	}
+
+	type Queue struct {
+		URL     string
+		Workers []int
+	}
+
+	t.Setenv("LP_CACHE_SIZE", "128")
+	t.Setenv("LP_CACHE_TTL", "5m")
+	t.Setenv("LP_QUEUE_URL", "amqp://localhost")
+	t.Setenv("LP_QUEUE_WORKERS", "1;2")
+
+	loader := New(WithPrefix("LP"), WithArraySeparator(";"))
+
+	var (
+		cache Cache
+		queue Queue
+	)
+
+	assert.NoError(t, loader.LoadPrefixed("CACHE", &cache))
+	assert.NoError(t, loader.LoadPrefixed("QUEUE", &queue))
+
+	assert.Equal(t, Cache{Size: 128, TTL: 5 * time.Minute}, cache)
+	assert.Equal(t, Queue{URL: "amqp://localhost", Workers: []int{1, 2}}, queue)
+
+	t.Run("loader is unchanged", func(t *testing.T) {
+		var cfg struct {
+			Cache Cache
+		}
+
+		assert.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, 128, cfg.Cache.Size)
+	})
+
+	t.Run("without loader prefix", func(t *testing.T) {
+		var cache Cache
+
+		assert.NoError(t, New().LoadPrefixed("LP_CACHE", &cache))
+		assert.Equal(t, 128, cache.Size)
+	})
+}
+
+func TestURLFields(t *testing.T) {
+	type Config struct {
+		API      *url.URL `env:"URLF_API_URL"`
+		Callback url.URL
+		Mirrors  []*url.URL
+		Unset    *url.URL
+	}
+
+	t.Setenv("URLF_API_URL", "https://api.example.com/v1?debug=1")
+	t.Setenv("URLF_CALLBACK", "http://localhost:8080/cb")
+	t.Setenv("URLF_MIRRORS", "https://a.example.com,s3://bucket/path")
+
+	var cfg Config
+	err := New(WithPrefix("URLF")).Load(&cfg)
+
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, cfg.API) {
+		assert.Equal(t, "api.example.com", cfg.API.Host)
+		assert.Equal(t, "/v1", cfg.API.Path)
+		assert.Equal(t, "1", cfg.API.Query().Get("debug"))
+	}
+
+	assert.Equal(t, "http://localhost:8080/cb", cfg.Callback.String())
+
+	if assert.Len(t, cfg.Mirrors, 2) {
+		assert.Equal(t, "s3", cfg.Mirrors[1].Scheme)
+	}
+
+	assert.Nil(t, cfg.Unset)
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tt := range []struct{ raw, msg string }{
+			{"", `url "" has no scheme`},
+			{"/relative/path", `url "/relative/path" has no scheme`},
+			{"api.example.com", "has no scheme"},
+			{"http://[::1", "missing ']' in host"},
+		} {
+			t.Setenv("URLF_API_URL", tt.raw)
+
+			err := New(WithPrefix("URLF")).Load(&Config{})
+			assert.ErrorContains(t, err, "cannot set field URLF_API_URL value", tt.raw)
+			assert.ErrorContains(t, err, tt.msg)
+		}
+	})
+}
+
+func TestHexBytes(t *testing.T) {
+	type Key []byte
+
+	type Config struct {
+		Bytes []byte   `format:"hex"`
+		Key   Key      `format:"hex"`
+		Salt  *[]byte  `format:"hex"`
+		Keys  [][]byte `format:"hex"`
+		Empty []byte   `format:"hex"`
+		Plain []byte
+	}
+
+	t.Setenv("HEXB_BYTES", "deadbeef")
+	t.Setenv("HEXB_KEY", "00FF10")
+	t.Setenv("HEXB_SALT", "0a0B")
+	t.Setenv("HEXB_KEYS", "01,02ff")
+	t.Setenv("HEXB_EMPTY", "")
+	t.Setenv("HEXB_PLAIN", "1,2")
+
+	var cfg Config
+	err := New(WithPrefix("HEXB")).Load(&cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, cfg.Bytes)
+	assert.Equal(t, Key{0x00, 0xff, 0x10}, cfg.Key)
+
+	if assert.NotNil(t, cfg.Salt) {
+		assert.Equal(t, []byte{0x0a, 0x0b}, *cfg.Salt)
+	}
+
+	assert.Equal(t, [][]byte{{0x01}, {0x02, 0xff}}, cfg.Keys)
+	assert.Empty(t, cfg.Empty)
+	assert.Equal(t, []byte{1, 2}, cfg.Plain, "untagged byte slices are lists")
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tt := range []struct{ raw, msg string }{
+			{"abc", "invalid hex value: encoding/hex: odd length hex string"},
+			{"zz", "invalid hex value: encoding/hex: invalid byte"},
+			{"0x0a", "invalid hex value"},
+		} {
+			t.Setenv("HEXB_BYTES", tt.raw)
+
+			err := New(WithPrefix("HEXB")).Load(&Config{})
+			assert.ErrorContains(t, err, "cannot set field HEXB_BYTES value", tt.raw)
+			assert.ErrorContains(t, err, tt.msg)
+		}
+	})
+}
+
+func TestIgnoredFields(t *testing.T) {
+	type Runtime struct {
+		Started time.Time
+		Host    string
+	}
+
+	type Config struct {
+		Name     string
+		Computed string         `env:"-"`
+		Runtime  Runtime        `env:"-"`
+		State    *Runtime       `env:"-"`
+		Handler  func()         `env:"-"`
+		Cache    map[string]int `env:"-"`
+	}
+
+	t.Setenv("IGN_NAME", "app")
+	t.Setenv("IGN_COMPUTED", "from-env")
+	t.Setenv("IGN_RUNTIME_HOST", "from-env")
+	t.Setenv("IGN_STATE_HOST", "from-env")
+
+	cfg := Config{Computed: "runtime"}
+	loader := New(WithPrefix("IGN"), WithFailFastOnUnsupportedType(), WithStrictPrefix())
+
+	err := loader.Load(&cfg)
+	assert.ErrorIs(t, err, ErrUnknownVariable, "the variables of ignored fields are not read")
+	assert.NotErrorIs(t, err, ErrUnsupportedType)
+
+	assert.Equal(t, "app", cfg.Name)
+	assert.Equal(t, "runtime", cfg.Computed)
+	assert.Empty(t, cfg.Runtime.Host)
+	assert.Nil(t, cfg.State)
+	assert.Nil(t, cfg.Cache)
+
+	assert.NoError(t, New().Check(&Config{}))
+}
@@ -3,14 +3,32 @@ package configtype
 import (
 	"encoding"
 	"encoding/base64"
+	stderrors "errors"
 
 	"github.com/pkg/errors"
 )
 
-var _ encoding.TextUnmarshaler = (*Base64)(nil)
+var (
+	_ encoding.TextUnmarshaler = (*Base64)(nil)
+	_ encoding.TextUnmarshaler = (*StrictBase64)(nil)
+)
+
+// base64Encodings are the encodings Base64 tries, in order.
+var base64Encodings = []struct {
+	name string
+	enc  *base64.Encoding
+}{
+	{name: "standard", enc: base64.StdEncoding},
+	{name: "raw standard", enc: base64.RawStdEncoding},
+	{name: "url", enc: base64.URLEncoding},
+	{name: "raw url", enc: base64.RawURLEncoding},
+}
 
 // Base64 represents a base64-encoded string value.
 // It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// The encoding is detected: the standard, raw standard (unpadded), URL and raw URL
+// encodings are tried in that order and the first one that decodes the text wins.
+// Use StrictBase64 to accept the standard encoding only.
 //
 // Example usage:
 //
@@ -34,18 +52,46 @@ var _ encoding.TextUnmarshaler = (*Base64)(nil)
 type Base64 string
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
-// It decodes the base64-encoded text into a string.
+// It decodes the base64-encoded text into a string, returning the errors of
+// all encodings when none of them can decode it.
 func (b *Base64) UnmarshalText(data []byte) error {
 	if len(data) == 0 {
 		return nil
 	}
 
+	errs := make([]error, 0, len(base64Encodings))
+
+	for _, e := range base64Encodings {
+		decoded, err := e.enc.DecodeString(string(data))
+		if err == nil {
+			*b = Base64(decoded)
+			return nil
+		}
+
+		errs = append(errs, errors.Wrapf(err, "%s encoding", e.name))
+	}
+
+	return errors.Wrapf(stderrors.Join(errs...), "failed to decode base64 string")
+}
+
+// StrictBase64 represents a base64-encoded string value that must use the
+// standard, padded encoding (base64.StdEncoding).
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+type StrictBase64 string
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It decodes the base64-encoded text into a string.
+func (b *StrictBase64) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
 	// Decode base64 string
 	decoded, err := base64.StdEncoding.DecodeString(string(data))
 	if err != nil {
 		return errors.Wrapf(err, "failed to decode base64 string")
 	}
 
-	*b = Base64(decoded)
+	*b = StrictBase64(decoded)
 	return nil
 }
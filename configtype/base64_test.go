@@ -60,3 +60,44 @@ func TestBase64(t *testing.T) {
 		})
 	}
 }
+
+func TestBase64Encodings(t *testing.T) {
+	// 0xfb 0xff encodes to characters that differ between the standard and URL alphabets
+	expected := "\xfb\xffok"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "standard", input: "+/9vaw=="},
+		{name: "raw standard", input: "+/9vaw"},
+		{name: "url", input: "-_9vaw=="},
+		{name: "raw url", input: "-_9vaw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Base64
+			assert.NoError(t, b.UnmarshalText([]byte(tt.input)))
+			assert.Equal(t, expected, string(b))
+
+			var strict StrictBase64
+			err := strict.UnmarshalText([]byte(tt.input))
+
+			if tt.name == "standard" {
+				assert.NoError(t, err)
+				assert.Equal(t, expected, string(strict))
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("all encodings fail", func(t *testing.T) {
+		var b Base64
+		err := b.UnmarshalText([]byte("not-base64!"))
+
+		assert.ErrorContains(t, err, "standard encoding")
+		assert.ErrorContains(t, err, "raw url encoding")
+	})
+}
@@ -0,0 +1,152 @@
+package configtype
+
+import (
+	"encoding"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*ByteSize)(nil)
+	_ encoding.TextMarshaler   = ByteSize(0)
+)
+
+// Byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+// byteSizeUnits maps lower-cased unit suffixes to their size.
+// Single letter suffixes use SI (power of 1000) units.
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KB,
+	"kb":  KB,
+	"m":   MB,
+	"mb":  MB,
+	"g":   GB,
+	"gb":  GB,
+	"t":   TB,
+	"tb":  TB,
+	"p":   PB,
+	"pb":  PB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+	"pib": PiB,
+}
+
+// ByteSize is a number of bytes that can be read from human-readable text
+// such as "512", "10MB" or "1.5GiB". Units are case-insensitive; SI units
+// (KB, MB, GB, TB, PB) are powers of 1000 and binary units (KiB, MiB, GiB,
+// TiB, PiB) are powers of 1024. Single letter units (K, M, G, T, P) are SI.
+//
+// It implements encoding.TextUnmarshaler, so the same value can be loaded from
+// environment variables and from JSON, YAML or TOML configuration files.
+//
+// Example usage:
+//
+//	type UploadConfig struct {
+//		MaxSize configtype.ByteSize `env:"MAX_UPLOAD_SIZE"`
+//	}
+//
+//	// export MAX_UPLOAD_SIZE=10MiB
+//
+//	var config UploadConfig
+//	if err := goconfig.Load(&config); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	fmt.Println(uint64(config.MaxSize)) // 10485760
+type ByteSize uint64
+
+// ParseByteSize parses a human-readable byte size such as "10MiB".
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	size, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, errors.Errorf("unknown byte size unit %q", s[i:])
+	}
+
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid byte size %q", s)
+	}
+
+	bytes := n * float64(size)
+	if bytes >= math.MaxUint64 {
+		return 0, errors.Errorf("byte size %q overflows uint64", s)
+	}
+
+	return ByteSize(bytes), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (b *ByteSize) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	v, err := ParseByteSize(string(data))
+	if err != nil {
+		return err
+	}
+
+	*b = v
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// String formats the size with the largest binary unit that divides it exactly,
+// e.g. "10MiB" or "1500B".
+func (b ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{
+		{PiB, "PiB"},
+		{TiB, "TiB"},
+		{GiB, "GiB"},
+		{MiB, "MiB"},
+		{KiB, "KiB"},
+	}
+
+	for _, u := range units {
+		if b >= u.size && b%u.size == 0 {
+			return strconv.FormatUint(uint64(b/u.size), 10) + u.name
+		}
+	}
+
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
@@ -0,0 +1,51 @@
+package configtype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ByteSize
+		wantErr  bool
+	}{
+		{name: "plain bytes", input: "512", expected: 512},
+		{name: "bytes unit", input: "512B", expected: 512},
+		{name: "si unit", input: "10MB", expected: 10 * MB},
+		{name: "binary unit", input: "512KiB", expected: 512 * KiB},
+		{name: "single letter unit", input: "2G", expected: 2 * GB},
+		{name: "case insensitive", input: "10mib", expected: 10 * MiB},
+		{name: "fraction", input: "1.5GiB", expected: 1536 * MiB},
+		{name: "space before unit", input: "1 TiB", expected: TiB},
+		{name: "empty input", input: "", expected: 0},
+		{name: "unknown unit", input: "10XB", wantErr: true},
+		{name: "negative", input: "-1MB", wantErr: true},
+		{name: "missing number", input: "MB", wantErr: true},
+		{name: "overflow", input: "100000PiB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b ByteSize
+			err := b.UnmarshalText([]byte(tt.input))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, b)
+		})
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	assert.Equal(t, "10MiB", (10 * MiB).String())
+	assert.Equal(t, "1500B", ByteSize(1500).String())
+	assert.Equal(t, "0B", ByteSize(0).String())
+}
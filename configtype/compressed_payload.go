@@ -0,0 +1,85 @@
+package configtype
+
+import (
+	"compress/gzip"
+	"encoding"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var _ encoding.TextUnmarshaler = (*CompressedPayload[any])(nil)
+
+// CompressedPayload represents configuration data delivered inline as base64 of
+// gzip of a JSON, YAML or TOML document, which fits large configurations into
+// size-limited environment variables.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// The base64 encoding is detected like Base64. Unlike files, the content is not
+// expanded with environment variables.
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//		// export APP_PAYLOAD="$(gzip -c config.yaml | base64 -w0)"
+//		Payload configtype.CompressedPayload[Settings] `env:"APP_PAYLOAD"`
+//	}
+//
+//	config := AppConfig{Payload: configtype.CompressedPayload[Settings]{Format: "yaml"}}
+//	if err := goconfig.Load(&config); err != nil {
+//		log.Fatal(err)
+//	}
+type CompressedPayload[T any] struct {
+	// Data contains the decoded configuration data
+	Data T
+	// Format is the format of the compressed document: "json" (the default when
+	// empty), "yaml", "yml" or "toml". It must be set before the payload is loaded.
+	Format string
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It base64-decodes and decompresses the text, then decodes the document into Data.
+func (p *CompressedPayload[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	name := strings.ToLower(p.Format)
+	if name == "" {
+		name = "json"
+	}
+
+	format, ok := fileFormats["."+name]
+	if !ok || name == "xml" {
+		return errors.Errorf("unsupported payload format %q", p.Format)
+	}
+
+	var compressed Base64
+	if err := compressed.UnmarshalText(data); err != nil {
+		return errors.Wrap(err, "invalid payload")
+	}
+
+	zr, err := gzip.NewReader(strings.NewReader(string(compressed)))
+	if err != nil {
+		return errors.Wrap(err, "failed to decompress payload")
+	}
+
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return errors.Wrap(err, "failed to decompress payload")
+	}
+
+	var v T
+	if err := format.decode(string(content), &v); err != nil {
+		return errors.Wrapf(err, "failed to parse %s payload", name)
+	}
+
+	p.Data = v
+
+	return nil
+}
+
+// Get returns the decoded configuration data.
+func (p *CompressedPayload[T]) Get() T {
+	return p.Data
+}
@@ -0,0 +1,83 @@
+package configtype
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type payloadConfig struct {
+	Name  string   `json:"name" yaml:"name" toml:"name"`
+	Hosts []string `json:"hosts" yaml:"hosts" toml:"hosts"`
+}
+
+// compress returns content gzipped and encoded with enc.
+func compress(t *testing.T, content string, enc *base64.Encoding) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	return enc.EncodeToString(buf.Bytes())
+}
+
+func TestCompressedPayload(t *testing.T) {
+	expected := payloadConfig{Name: "api", Hosts: []string{"a", "b"}}
+
+	t.Run("yaml from env", func(t *testing.T) {
+		type Config struct {
+			Payload CompressedPayload[payloadConfig] `env:"COMPRESSED_PAYLOAD_TEST"`
+		}
+
+		t.Setenv("COMPRESSED_PAYLOAD_TEST", compress(t, "name: api\nhosts: [a, b]\n", base64.StdEncoding))
+
+		cfg := Config{Payload: CompressedPayload[payloadConfig]{Format: "yaml"}}
+		require.NoError(t, goconfig.Load(&cfg))
+		assert.Equal(t, expected, cfg.Payload.Get())
+	})
+
+	t.Run("formats", func(t *testing.T) {
+		tests := map[string]string{
+			"":     `{"name": "api", "hosts": ["a", "b"]}`,
+			"JSON": `{"name": "api", "hosts": ["a", "b"]}`,
+			"yml":  "name: api\nhosts: [a, b]\n",
+			"toml": "name = \"api\"\nhosts = [\"a\", \"b\"]\n",
+		}
+
+		for format, content := range tests {
+			p := CompressedPayload[payloadConfig]{Format: format}
+			require.NoError(t, p.UnmarshalText([]byte(compress(t, content, base64.RawURLEncoding))), format)
+			assert.Equal(t, expected, p.Data, format)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			format  string
+			payload string
+			wantErr string
+		}{
+			{name: "base64", payload: "not base64!", wantErr: "invalid payload: failed to decode base64 string"},
+			{name: "gzip", payload: base64.StdEncoding.EncodeToString([]byte("plain")), wantErr: "failed to decompress payload"},
+			{name: "document", payload: compress(t, "{", base64.StdEncoding), wantErr: "failed to parse json payload"},
+			{name: "format", format: "xml", payload: "x", wantErr: `unsupported payload format "xml"`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				p := CompressedPayload[payloadConfig]{Format: tt.format}
+				assert.ErrorContains(t, p.UnmarshalText([]byte(tt.payload)), tt.wantErr)
+			})
+		}
+	})
+}
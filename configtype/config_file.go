@@ -0,0 +1,71 @@
+package configtype
+
+import (
+	"encoding"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ ConfigFile[any] = (*JSONFile[any])(nil)
+	_ ConfigFile[any] = (*YAMLFile[any])(nil)
+	_ ConfigFile[any] = (*TOMLFile[any])(nil)
+	_ ConfigFile[any] = (*XMLFile[any])(nil)
+)
+
+// ConfigFile is implemented by all file-based configuration types,
+// which allows writing code that works with any configuration format.
+//
+// Example usage:
+//
+//	func loadFile[T any](f configtype.ConfigFile[T], path string) (T, error) {
+//		if err := f.UnmarshalText([]byte(path)); err != nil {
+//			var zero T
+//			return zero, err
+//		}
+//
+//		return f.Get(), nil
+//	}
+//
+//	cfg, err := loadFile[DBConfig](&configtype.YAMLFile[DBConfig]{}, "db.yaml")
+type ConfigFile[T any] interface {
+	encoding.TextUnmarshaler
+	// Reload reads and parses the file again.
+	Reload() error
+	// Get returns the parsed configuration data.
+	Get() T
+}
+
+// readFile reads name with read, or with os.ReadFile when read is nil.
+func readFile(read func(name string) ([]byte, error), name string) ([]byte, error) {
+	if read == nil {
+		read = os.ReadFile
+	}
+
+	return read(name)
+}
+
+// marshalText returns path, or data encoded with format when marshalData is set.
+func marshalText(path string, data any, marshalData bool, format fileFormat) ([]byte, error) {
+	if !marshalData {
+		return []byte(path), nil
+	}
+
+	text, err := format.encode(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal config file data: %s", path)
+	}
+
+	return text, nil
+}
+
+// marshalJSON returns path as a JSON string, or data when marshalData is set.
+func marshalJSON(path string, data any, marshalData bool) ([]byte, error) {
+	if !marshalData {
+		return json.Marshal(path)
+	}
+
+	return json.Marshal(data)
+}
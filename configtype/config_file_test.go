@@ -0,0 +1,209 @@
+package configtype
+
+import (
+	"encoding"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type polymorphicConfig struct {
+	Name string `json:"name" yaml:"name" toml:"name" xml:"name"`
+}
+
+func loadConfigFile[T any](f ConfigFile[T], path string) (T, error) {
+	if err := f.UnmarshalText([]byte(path)); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return f.Get(), nil
+}
+
+func TestConfigFileInterface(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    ConfigFile[polymorphicConfig]
+		content string
+	}{
+		{name: "config.json", file: &JSONFile[polymorphicConfig]{}, content: `{"name": "json"}`},
+		{name: "config.yaml", file: &YAMLFile[polymorphicConfig]{}, content: `name: yaml`},
+		{name: "config.toml", file: &TOMLFile[polymorphicConfig]{}, content: `name = "toml"`},
+		{name: "config.xml", file: &XMLFile[polymorphicConfig]{}, content: `<config><name>xml</name></config>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, tt.name)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			data, err := loadConfigFile(tt.file, path)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Ext(tt.name)[1:], data.Name)
+			assert.NoError(t, tt.file.Reload())
+		})
+	}
+}
+
+func TestReadFileFunc(t *testing.T) {
+	files := map[string]string{
+		"/virtual/config.json": `{"name": "json"}`,
+		"/virtual/config.yaml": `name: yaml`,
+		"/virtual/config.toml": `name = "toml"`,
+		"/virtual/config.xml":  `<config><name>xml</name></config>`,
+	}
+
+	var reads []string
+
+	readFile := func(name string) ([]byte, error) {
+		reads = append(reads, name)
+
+		content, ok := files[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+
+		return []byte(content), nil
+	}
+
+	tests := []struct {
+		path string
+		file ConfigFile[polymorphicConfig]
+	}{
+		{path: "/virtual/config.json", file: &JSONFile[polymorphicConfig]{ReadFile: readFile}},
+		{path: "/virtual/config.yaml", file: &YAMLFile[polymorphicConfig]{ReadFile: readFile}},
+		{path: "/virtual/config.toml", file: &TOMLFile[polymorphicConfig]{ReadFile: readFile}},
+		{path: "/virtual/config.xml", file: &XMLFile[polymorphicConfig]{ReadFile: readFile}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			data, err := loadConfigFile(tt.file, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Ext(tt.path)[1:], data.Name)
+		})
+	}
+
+	assert.Equal(t, []string{
+		"/virtual/config.json",
+		"/virtual/config.yaml",
+		"/virtual/config.toml",
+		"/virtual/config.xml",
+	}, reads)
+
+	t.Run("read error", func(t *testing.T) {
+		_, err := loadConfigFile[polymorphicConfig](&JSONFile[polymorphicConfig]{ReadFile: readFile}, "/virtual/missing.json")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestMarshalFile(t *testing.T) {
+	data := polymorphicConfig{Name: "app"}
+
+	type marshaler interface {
+		encoding.TextMarshaler
+		json.Marshaler
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		path     marshaler
+		data     marshaler
+		expected string
+	}{
+		{
+			name:     "json",
+			file:     "config.json",
+			path:     JSONFile[polymorphicConfig]{FilePath: "config.json", Data: data},
+			data:     JSONFile[polymorphicConfig]{FilePath: "config.json", Data: data, MarshalData: true},
+			expected: `{"name":"app"}`,
+		},
+		{
+			name:     "yaml",
+			file:     "config.yaml",
+			path:     YAMLFile[polymorphicConfig]{FilePath: "config.yaml", Data: data},
+			data:     YAMLFile[polymorphicConfig]{FilePath: "config.yaml", Data: data, MarshalData: true},
+			expected: "name: app\n",
+		},
+		{
+			name:     "toml",
+			file:     "config.toml",
+			path:     TOMLFile[polymorphicConfig]{FilePath: "config.toml", Data: data},
+			data:     TOMLFile[polymorphicConfig]{FilePath: "config.toml", Data: data, MarshalData: true},
+			expected: "name = \"app\"\n",
+		},
+		{
+			name:     "xml",
+			file:     "config.xml",
+			path:     XMLFile[polymorphicConfig]{FilePath: "config.xml", Data: data},
+			data:     XMLFile[polymorphicConfig]{FilePath: "config.xml", Data: data, MarshalData: true},
+			expected: `<polymorphicConfig><name>app</name></polymorphicConfig>`,
+		},
+		{
+			name:     "file by extension",
+			file:     "config.json",
+			path:     File[polymorphicConfig]{FilePath: "config.json", Data: data},
+			data:     File[polymorphicConfig]{FilePath: "${CONFIG_DIR}/config.yml", Data: data, MarshalData: true},
+			expected: "name: app\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.path.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.file, string(text))
+
+			js, err := tt.path.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, `"`+tt.file+`"`, string(js))
+
+			text, err = tt.data.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(text))
+
+			js, err = tt.data.MarshalJSON()
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"name":"app"}`, string(js))
+		})
+	}
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		_, err := File[polymorphicConfig]{FilePath: "config.ini", MarshalData: true}.MarshalText()
+		assert.ErrorContains(t, err, "unsupported config file extension")
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"name": "app"}`), 0o644))
+
+		type Config struct {
+			App JSONFile[polymorphicConfig]
+		}
+
+		var cfg Config
+		require.NoError(t, cfg.App.UnmarshalText([]byte(path)))
+
+		out, err := json.Marshal(cfg)
+		require.NoError(t, err)
+
+		var back struct{ App string }
+		require.NoError(t, json.Unmarshal(out, &back))
+		assert.Equal(t, path, back.App)
+
+		text, err := cfg.App.MarshalText()
+		require.NoError(t, err)
+
+		var again JSONFile[polymorphicConfig]
+		require.NoError(t, again.UnmarshalText(text))
+		assert.Equal(t, cfg.App.Data, again.Data)
+	})
+}
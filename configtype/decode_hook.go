@@ -0,0 +1,512 @@
+package configtype
+
+import (
+	"encoding"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DecodeHookFunc converts a decoded value before it is stored into a field of
+// type to, in the style of mapstructure decode hooks. from is the type of data
+// as produced by the format decoder, e.g. string, float64 or map[string]any.
+// A hook returns data unchanged for conversions it does not handle.
+type DecodeHookFunc func(from, to reflect.Type, data any) (any, error)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	ipType              = reflect.TypeOf(net.IP{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// ComposeDecodeHooks returns a hook running hooks in order, each one receiving
+// the value returned by the previous one.
+func ComposeDecodeHooks(hooks ...DecodeHookFunc) DecodeHookFunc {
+	return func(from, to reflect.Type, data any) (any, error) {
+		var err error
+
+		for _, hook := range hooks {
+			if data, err = hook(from, to, data); err != nil {
+				return nil, err
+			}
+
+			if data == nil {
+				return nil, nil
+			}
+
+			from = reflect.TypeOf(data)
+		}
+
+		return data, nil
+	}
+}
+
+// StringToDurationHook converts strings such as "1m30s" to time.Duration.
+func StringToDurationHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != durationType {
+		return data, nil
+	}
+
+	return time.ParseDuration(reflect.ValueOf(data).String())
+}
+
+// StringToTimeHook returns a hook converting strings to time.Time with layout,
+// e.g. time.DateOnly, for timestamps that are not in RFC 3339. Strings in
+// RFC 3339 are accepted too, as they are without a hook.
+func StringToTimeHook(layout string) DecodeHookFunc {
+	return func(from, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != timeType {
+			return data, nil
+		}
+
+		s := reflect.ValueOf(data).String()
+
+		ts, err := time.Parse(layout, s)
+		if err != nil {
+			if rfc, rfcErr := time.Parse(time.RFC3339, s); rfcErr == nil {
+				return rfc, nil
+			}
+
+			return nil, err
+		}
+
+		return ts, nil
+	}
+}
+
+// StringToIPHook converts strings such as "10.0.0.1" or "::1" to net.IP.
+func StringToIPHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != ipType {
+		return data, nil
+	}
+
+	s := reflect.ValueOf(data).String()
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.Errorf("invalid IP address %q", s)
+	}
+
+	return ip, nil
+}
+
+// TextUnmarshalerHook converts strings with the UnmarshalText method of types
+// implementing encoding.TextUnmarshaler, e.g. the types of this package.
+func TextUnmarshalerHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || !reflect.PointerTo(to).Implements(textUnmarshalerType) {
+		return data, nil
+	}
+
+	v := reflect.New(to)
+	if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(reflect.ValueOf(data).String())); err != nil {
+		return nil, err
+	}
+
+	return v.Elem().Interface(), nil
+}
+
+// decodeWithHook decodes content into the generic structure of the format and
+// then converts it into v, calling hook for every value. It also returns an
+// ErrUnknownField error for each key that matches no field of v, which strict
+// callers report.
+func decodeWithHook(content string, format fileFormat, hook DecodeHookFunc, v any) (unknown, err error) {
+	if format.tag == "" {
+		return nil, errors.New("decode hooks are not supported for this format")
+	}
+
+	var raw any
+	if err := format.decode(content, &raw); err != nil {
+		return nil, err
+	}
+
+	var unknownKeys []error
+
+	d := hookDecoder{hook: hook, tag: format.tag, unknown: &unknownKeys}
+	if err := d.decode(raw, reflect.ValueOf(v).Elem(), ""); err != nil {
+		return nil, err
+	}
+
+	return stderrors.Join(unknownKeys...), nil
+}
+
+// hookDecoder converts generic decoded values (maps, slices and scalars) into
+// typed values, matching map keys to struct fields by the format's tag or,
+// without a tag, by field name ignoring case.
+type hookDecoder struct {
+	hook DecodeHookFunc
+	tag  string
+	// unknown collects the keys of decoded maps that match no struct field
+	unknown *[]error
+}
+
+func (d hookDecoder) decode(data any, out reflect.Value, path string) error {
+	if data == nil {
+		return nil
+	}
+
+	if d.hook != nil {
+		var err error
+		if data, err = d.hook(reflect.TypeOf(data), out.Type(), data); err != nil {
+			return errors.Wrapf(err, "decode hook for %s", d.name(path))
+		}
+
+		if data == nil {
+			return nil
+		}
+	}
+
+	if out.Kind() == reflect.Pointer {
+		if out.IsNil() {
+			out.Set(reflect.New(out.Type().Elem()))
+		}
+
+		return d.decode(data, out.Elem(), path)
+	}
+
+	in := reflect.ValueOf(data)
+	if in.Type().AssignableTo(out.Type()) {
+		out.Set(in)
+		return nil
+	}
+
+	// JSON has no timestamps and quoted YAML or TOML ones are strings, which
+	// are read as RFC 3339 like encoding/json does
+	if out.Type() == timeType && in.Kind() == reflect.String {
+		return d.decodeTime(in.String(), out, path)
+	}
+
+	// strings are read by UnmarshalText like the format decoders do without a hook
+	if in.Kind() == reflect.String && out.CanAddr() && out.Addr().Type().Implements(textUnmarshalerType) {
+		if err := out.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(in.String())); err != nil {
+			return errors.Wrapf(err, "cannot decode %s at %s", out.Type(), d.name(path))
+		}
+
+		return nil
+	}
+
+	switch out.Kind() {
+	case reflect.Struct:
+		if err := d.decodeStruct(in, out, path); err != nil {
+			return err
+		}
+
+		d.collectUnknown(in, out.Type(), path)
+
+		return nil
+	case reflect.Map:
+		return d.decodeMap(in, out, path)
+	case reflect.Slice:
+		return d.decodeSlice(in, out, path)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return d.decodeNumber(in, out, path)
+	}
+
+	if in.Type().ConvertibleTo(out.Type()) && in.Kind() == out.Kind() {
+		out.Set(in.Convert(out.Type()))
+		return nil
+	}
+
+	return errors.Errorf("cannot decode %s into %s at %s", in.Type(), out.Type(), d.name(path))
+}
+
+// decodeTime parses an RFC 3339 timestamp into out.
+func (d hookDecoder) decodeTime(s string, out reflect.Value, path string) error {
+	ts, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return errors.Wrapf(err, "cannot decode time at %s", d.name(path))
+	}
+
+	out.Set(reflect.ValueOf(ts))
+
+	return nil
+}
+
+// collectUnknown records the keys of in that match no field of t, including
+// the fields of its untagged embedded structs.
+func (d hookDecoder) collectUnknown(in reflect.Value, t reflect.Type, path string) {
+	if d.unknown == nil {
+		return
+	}
+
+	iter := in.MapRange()
+	for iter.Next() {
+		key, ok := iter.Key().Interface().(string)
+		if ok && d.isKnownKey(t, key) {
+			continue
+		}
+
+		*d.unknown = append(*d.unknown, errors.Wrapf(ErrUnknownField, "key %s", joinPath(path, fmt.Sprint(iter.Key().Interface()))))
+	}
+}
+
+// isKnownKey reports whether key matches a field of t, like mapValue does.
+func (d hookDecoder) isKnownKey(t reflect.Type, key string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		tf := t.Field(i)
+		if !tf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tf.Tag.Get(d.tag), ",")
+
+		switch {
+		case name == "-":
+			continue
+		case tf.Anonymous && name == "" && tf.Type.Kind() == reflect.Struct:
+			if d.isKnownKey(tf.Type, key) {
+				return true
+			}
+		case name != "" && key == name, name == "" && strings.EqualFold(key, tf.Name):
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d hookDecoder) decodeStruct(in, out reflect.Value, path string) error {
+	if in.Kind() != reflect.Map {
+		return errors.Errorf("cannot decode %s into %s at %s", in.Type(), out.Type(), d.name(path))
+	}
+
+	t := out.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		tf := t.Field(i)
+		if !tf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tf.Tag.Get(d.tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		// untagged embedded structs are read from the same map
+		if tf.Anonymous && name == "" && tf.Type.Kind() == reflect.Struct {
+			if err := d.decodeStruct(in, out.Field(i), path); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, ok := mapValue(in, name, tf.Name)
+		if !ok {
+			continue
+		}
+
+		if err := d.decode(value, out.Field(i), joinPath(path, tf.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d hookDecoder) decodeMap(in, out reflect.Value, path string) error {
+	if in.Kind() != reflect.Map {
+		return errors.Errorf("cannot decode %s into %s at %s", in.Type(), out.Type(), d.name(path))
+	}
+
+	if out.IsNil() {
+		out.Set(reflect.MakeMapWithSize(out.Type(), in.Len()))
+	}
+
+	iter := in.MapRange()
+	for iter.Next() {
+		key := reflect.New(out.Type().Key()).Elem()
+		if err := d.decode(iter.Key().Interface(), key, path); err != nil {
+			return err
+		}
+
+		elem := reflect.New(out.Type().Elem()).Elem()
+		if err := d.decode(iter.Value().Interface(), elem, joinPath(path, fmt.Sprint(iter.Key().Interface()))); err != nil {
+			return err
+		}
+
+		out.SetMapIndex(key, elem)
+	}
+
+	return nil
+}
+
+func (d hookDecoder) decodeSlice(in, out reflect.Value, path string) error {
+	if in.Kind() != reflect.Slice && in.Kind() != reflect.Array {
+		return errors.Errorf("cannot decode %s into %s at %s", in.Type(), out.Type(), d.name(path))
+	}
+
+	slice := reflect.MakeSlice(out.Type(), in.Len(), in.Len())
+
+	for i := 0; i < in.Len(); i++ {
+		if err := d.decode(in.Index(i).Interface(), slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	out.Set(slice)
+
+	return nil
+}
+
+// decodeNumber converts between number kinds, rejecting fractions for integer
+// fields and values that overflow the field. Integers are converted without
+// going through float64, which would round those above 2^53.
+func (d hookDecoder) decodeNumber(in, out reflect.Value, path string) error {
+	var ok bool
+
+	switch {
+	case !in.CanInt() && !in.CanUint() && !in.CanFloat():
+		return errors.Errorf("cannot decode %s into %s at %s", in.Type(), out.Type(), d.name(path))
+	case out.CanFloat():
+		ok = setFloat(in, out)
+	case in.CanFloat():
+		ok = setIntegerFromFloat(in.Float(), out)
+	case out.CanInt():
+		ok = setInt(in, out)
+	default:
+		ok = setUint(in, out)
+	}
+
+	if !ok {
+		return errors.Errorf("value %v does not fit %s at %s", in, out.Type(), d.name(path))
+	}
+
+	return nil
+}
+
+// setFloat sets the number in to the float out.
+func setFloat(in, out reflect.Value) bool {
+	var f float64
+
+	switch {
+	case in.CanInt():
+		f = float64(in.Int())
+	case in.CanUint():
+		f = float64(in.Uint())
+	default:
+		f = in.Float()
+	}
+
+	if out.OverflowFloat(f) {
+		return false
+	}
+
+	out.SetFloat(f)
+
+	return true
+}
+
+// setIntegerFromFloat sets f to the integer out when it is a whole number in range.
+func setIntegerFromFloat(f float64, out reflect.Value) bool {
+	if f != math.Trunc(f) {
+		return false
+	}
+
+	// 2^63 and 2^64 are exact floats; converting a float out of range is implementation-defined
+	if out.CanInt() {
+		if f < math.MinInt64 || f >= 1<<63 || out.OverflowInt(int64(f)) {
+			return false
+		}
+
+		out.SetInt(int64(f))
+
+		return true
+	}
+
+	if f < 0 || f >= 1<<64 || out.OverflowUint(uint64(f)) {
+		return false
+	}
+
+	out.SetUint(uint64(f))
+
+	return true
+}
+
+// setInt sets the integer in to the signed integer out.
+func setInt(in, out reflect.Value) bool {
+	var i int64
+
+	if in.CanUint() {
+		if in.Uint() > math.MaxInt64 {
+			return false
+		}
+
+		i = int64(in.Uint())
+	} else {
+		i = in.Int()
+	}
+
+	if out.OverflowInt(i) {
+		return false
+	}
+
+	out.SetInt(i)
+
+	return true
+}
+
+// setUint sets the integer in to the unsigned integer out.
+func setUint(in, out reflect.Value) bool {
+	var u uint64
+
+	if in.CanInt() {
+		if in.Int() < 0 {
+			return false
+		}
+
+		u = uint64(in.Int())
+	} else {
+		u = in.Uint()
+	}
+
+	if out.OverflowUint(u) {
+		return false
+	}
+
+	out.SetUint(u)
+
+	return true
+}
+
+func (hookDecoder) name(path string) string {
+	if path == "" {
+		return "root"
+	}
+
+	return path
+}
+
+// mapValue returns the value of the map for the tag name, or for the field name ignoring case.
+func mapValue(m reflect.Value, tagName, fieldName string) (any, bool) {
+	iter := m.MapRange()
+	for iter.Next() {
+		key, ok := iter.Key().Interface().(string)
+		if !ok {
+			continue
+		}
+
+		if tagName != "" && key == tagName || tagName == "" && strings.EqualFold(key, fieldName) {
+			return iter.Value().Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
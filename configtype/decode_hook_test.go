@@ -0,0 +1,423 @@
+package configtype
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookLevel int
+
+type hookServer struct {
+	Addr    net.IP        `json:"addr" yaml:"addr" toml:"addr"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+}
+
+type hookConfig struct {
+	Name     string               `json:"name" yaml:"name" toml:"name"`
+	Level    hookLevel            `json:"level" yaml:"level" toml:"level"`
+	Port     uint16               `json:"port" yaml:"port" toml:"port"`
+	Server   *hookServer          `json:"server" yaml:"server" toml:"server"`
+	Replicas []hookServer         `json:"replicas" yaml:"replicas" toml:"replicas"`
+	Limits   map[string]Duration  `json:"limits" yaml:"limits" toml:"limits"`
+	Windows  map[string]*ByteSize `json:"windows" yaml:"windows" toml:"windows"`
+	Untagged string
+}
+
+// levelHook is a custom hook reading levels from their names.
+func levelHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(hookLevel(0)) {
+		return data, nil
+	}
+
+	level, ok := map[string]hookLevel{"debug": 0, "info": 1, "warn": 2, "error": 3}[data.(string)]
+	if !ok {
+		return nil, errors.New("unknown level")
+	}
+
+	return level, nil
+}
+
+func TestDecodeHook(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+name: api
+level: warn
+port: 8080
+server: {addr: 10.0.0.1, timeout: 5s}
+replicas:
+  - {addr: "::1", timeout: 1m}
+limits: {read: 2s}
+windows: {burst: 1KiB}
+untagged: yes
+`,
+		"config.json": `{
+	"name": "api", "level": "warn", "port": 8080,
+	"server": {"addr": "10.0.0.1", "timeout": "5s"},
+	"replicas": [{"addr": "::1", "timeout": "1m"}],
+	"limits": {"read": "2s"},
+	"windows": {"burst": "1KiB"},
+	"Untagged": "yes"
+}`,
+		"config.toml": `
+name = "api"
+level = "warn"
+port = 8080
+untagged = "yes"
+limits = {read = "2s"}
+windows = {burst = "1KiB"}
+server = {addr = "10.0.0.1", timeout = "5s"}
+
+[[replicas]]
+addr = "::1"
+timeout = "1m"
+`,
+	}
+
+	burst := ByteSize(1024)
+	expected := hookConfig{
+		Name:     "api",
+		Level:    2,
+		Port:     8080,
+		Server:   &hookServer{Addr: net.ParseIP("10.0.0.1"), Timeout: 5 * time.Second},
+		Replicas: []hookServer{{Addr: net.ParseIP("::1"), Timeout: time.Minute}},
+		Limits:   map[string]Duration{"read": Duration(2 * time.Second)},
+		Windows:  map[string]*ByteSize{"burst": &burst},
+		Untagged: "yes",
+	}
+
+	hook := ComposeDecodeHooks(levelHook, StringToDurationHook, StringToIPHook, TextUnmarshalerHook)
+
+	readFile := func(name string) ([]byte, error) {
+		return []byte(files[name]), nil
+	}
+
+	for name := range files {
+		t.Run(name, func(t *testing.T) {
+			f := File[hookConfig]{DecodeHook: hook, ReadFile: readFile}
+			require.NoError(t, f.UnmarshalText([]byte(name)))
+			assert.Equal(t, expected, f.Data)
+		})
+	}
+
+	t.Run("hook errors", func(t *testing.T) {
+		f := File[hookConfig]{DecodeHook: hook, ReadFile: func(string) ([]byte, error) {
+			return []byte(`{"server": {"addr": "not-an-ip"}}`), nil
+		}}
+
+		err := f.UnmarshalText([]byte("config.json"))
+		assert.ErrorContains(t, err, `decode hook for Server.Addr: invalid IP address "not-an-ip"`)
+	})
+
+	t.Run("type errors", func(t *testing.T) {
+		tests := map[string]string{
+			`{"port": 80.5}`:    "value 80.5 does not fit uint16 at Port",
+			`{"port": 70000}`:   "value 70000 does not fit uint16 at Port",
+			`{"name": ["a"]}`:   "cannot decode []interface {} into string at Name",
+			`{"replicas": "a"}`: "cannot decode string into []configtype.hookServer at Replicas",
+		}
+
+		for content, msg := range tests {
+			f := File[hookConfig]{DecodeHook: hook, ReadFile: func(string) ([]byte, error) {
+				return []byte(content), nil
+			}}
+
+			assert.ErrorContains(t, f.UnmarshalText([]byte("config.json")), msg)
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		f := File[hookConfig]{DecodeHook: hook, ReadFile: readFile}
+		assert.ErrorContains(t, f.UnmarshalText([]byte("config.xml")), "decode hooks are not supported")
+	})
+}
+
+func TestStringToDurationHookMaps(t *testing.T) {
+	type limits struct {
+		Limits   map[string]time.Duration            `json:"limits" yaml:"limits" toml:"limits"`
+		Optional map[string]*time.Duration           `json:"optional" yaml:"optional" toml:"optional"`
+		Nested   map[string]map[string]time.Duration `json:"nested" yaml:"nested" toml:"nested"`
+		Timeout  time.Duration                       `json:"timeout" yaml:"timeout" toml:"timeout"`
+		Windows  []map[string]time.Duration          `json:"windows" yaml:"windows" toml:"windows"`
+	}
+
+	files := map[string]string{
+		"limits.json": `{
+	"limits": {"read": "5s", "write": "10s"},
+	"optional": {"idle": "1m"},
+	"nested": {"db": {"connect": "2s"}},
+	"timeout": "1h30m",
+	"windows": [{"burst": "100ms"}]
+}`,
+		"limits.yaml": `
+limits: {read: 5s, write: 10s}
+optional: {idle: 1m}
+nested: {db: {connect: 2s}}
+timeout: 1h30m
+windows: [{burst: 100ms}]
+`,
+		"limits.toml": `
+limits = {read = "5s", write = "10s"}
+optional = {idle = "1m"}
+nested = {db = {connect = "2s"}}
+timeout = "1h30m"
+windows = [{burst = "100ms"}]
+`,
+	}
+
+	idle := time.Minute
+	expected := limits{
+		Limits:   map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second},
+		Optional: map[string]*time.Duration{"idle": &idle},
+		Nested:   map[string]map[string]time.Duration{"db": {"connect": 2 * time.Second}},
+		Timeout:  90 * time.Minute,
+		Windows:  []map[string]time.Duration{{"burst": 100 * time.Millisecond}},
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		return []byte(files[name]), nil
+	}
+
+	t.Run("json file", func(t *testing.T) {
+		f := JSONFile[limits]{DecodeHook: StringToDurationHook, ReadFile: readFile}
+		require.NoError(t, f.UnmarshalText([]byte("limits.json")))
+		assert.Equal(t, expected, f.Data)
+	})
+
+	t.Run("yaml file", func(t *testing.T) {
+		f := YAMLFile[limits]{DecodeHook: StringToDurationHook, ReadFile: readFile}
+		require.NoError(t, f.UnmarshalText([]byte("limits.yaml")))
+		assert.Equal(t, expected, f.Data)
+	})
+
+	t.Run("toml file", func(t *testing.T) {
+		f := TOMLFile[limits]{DecodeHook: StringToDurationHook, ReadFile: readFile}
+		require.NoError(t, f.UnmarshalText([]byte("limits.toml")))
+		assert.Equal(t, expected, f.Data)
+	})
+
+	for name := range files {
+		t.Run("strict file "+name, func(t *testing.T) {
+			f := File[limits]{DecodeHook: StringToDurationHook, Strict: true, ReadFile: readFile}
+			require.NoError(t, f.UnmarshalText([]byte(name)))
+			assert.Equal(t, expected, f.Data)
+		})
+	}
+
+	t.Run("json without hook", func(t *testing.T) {
+		f := JSONFile[limits]{ReadFile: readFile}
+		assert.Error(t, f.UnmarshalText([]byte("limits.json")))
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		f := JSONFile[limits]{DecodeHook: StringToDurationHook, ReadFile: func(string) ([]byte, error) {
+			return []byte(`{"limits": {"read": "soon"}}`), nil
+		}}
+
+		assert.ErrorContains(t, f.UnmarshalText([]byte("limits.json")), "decode hook for Limits.read")
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		content := `{"limits": {"read": "5s"}, "timeout": "1s", "retries": 3, "nested": {"db": {"x": "1s"}}}`
+
+		f := JSONFile[limits]{DecodeHook: StringToDurationHook, DisallowUnknownFields: true, ReadFile: func(string) ([]byte, error) {
+			return []byte(content), nil
+		}}
+
+		err := f.UnmarshalText([]byte("limits.json"))
+		assert.ErrorIs(t, err, ErrUnknownField)
+		assert.ErrorContains(t, err, "key retries")
+		assert.Equal(t, time.Second, f.Data.Timeout, "known fields are still decoded")
+	})
+
+	t.Run("embedded keys are known", func(t *testing.T) {
+		type Base struct {
+			Timeout time.Duration `yaml:"timeout"`
+		}
+
+		type config struct {
+			Base
+			Name string
+		}
+
+		f := File[config]{DecodeHook: StringToDurationHook, Strict: true, ReadFile: func(string) ([]byte, error) {
+			return []byte("timeout: 2s\nname: api\nport: 80\n"), nil
+		}}
+
+		err := f.UnmarshalText([]byte("config.yaml"))
+		assert.EqualError(t, err, "config file: config.yaml: key port: unknown field")
+		assert.Equal(t, config{Base: Base{Timeout: 2 * time.Second}, Name: "api"}, f.Data)
+	})
+}
+
+func TestTimeValues(t *testing.T) {
+	type window struct {
+		Start time.Time  `json:"start" yaml:"start" toml:"start"`
+		End   *time.Time `json:"end" yaml:"end" toml:"end"`
+		Day   time.Time  `json:"day" yaml:"day" toml:"day"`
+	}
+
+	// JSON timestamps are strings; YAML and TOML have native ones, quoted here for end
+	files := map[string]string{
+		"window.json": `{"start": "2026-03-01T09:30:00Z", "end": "2026-03-02T10:00:00.5+02:00", "day": "2026-03-01T00:00:00Z"}`,
+		"window.yaml": "start: 2026-03-01T09:30:00Z\nend: \"2026-03-02T10:00:00.5+02:00\"\nday: 2026-03-01\n",
+		"window.toml": "start = 2026-03-01T09:30:00Z\nend = \"2026-03-02T10:00:00.5+02:00\"\nday = 2026-03-01T00:00:00Z\n",
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		return []byte(files[name]), nil
+	}
+
+	start := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 2, 8, 0, 0, 5e8, time.UTC)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for name := range files {
+		for _, hook := range []DecodeHookFunc{nil, StringToDurationHook} {
+			t.Run(fmt.Sprintf("%s hook=%t", name, hook != nil), func(t *testing.T) {
+				f := File[window]{DecodeHook: hook, ReadFile: readFile}
+				require.NoError(t, f.UnmarshalText([]byte(name)))
+
+				assert.True(t, start.Equal(f.Data.Start), f.Data.Start)
+				require.NotNil(t, f.Data.End)
+				assert.True(t, end.Equal(*f.Data.End), f.Data.End)
+				assert.True(t, day.Equal(f.Data.Day), f.Data.Day)
+			})
+		}
+	}
+
+	t.Run("layout hook", func(t *testing.T) {
+		f := JSONFile[window]{
+			DecodeHook: StringToTimeHook(time.DateOnly),
+			ReadFile: func(string) ([]byte, error) {
+				return []byte(`{"start": "2026-03-01T09:30:00Z", "day": "2026-03-01"}`), nil
+			},
+		}
+		require.NoError(t, f.UnmarshalText([]byte("window.json")))
+
+		assert.True(t, start.Equal(f.Data.Start), "RFC 3339 still works")
+		assert.True(t, day.Equal(f.Data.Day))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		f := JSONFile[window]{
+			DecodeHook: StringToDurationHook,
+			ReadFile: func(string) ([]byte, error) {
+				return []byte(`{"day": "March 1st"}`), nil
+			},
+		}
+
+		err := f.UnmarshalText([]byte("window.json"))
+		assert.ErrorContains(t, err, "cannot decode time at Day")
+	})
+}
+
+func TestDecodeHookLargeIntegers(t *testing.T) {
+	type ids struct {
+		ID     int64   `yaml:"id" toml:"id"`
+		Max    int64   `yaml:"max" toml:"max"`
+		Min    int64   `yaml:"min" toml:"min"`
+		Unsig  uint64  `yaml:"unsig" toml:"unsig"`
+		UMax   uint64  `yaml:"umax" toml:"umax"`
+		Ratio  float64 `yaml:"ratio" toml:"ratio"`
+		Rounds int     `yaml:"rounds" toml:"rounds"`
+	}
+
+	load := func(name, content string) (ids, error) {
+		f := File[ids]{DecodeHook: StringToDurationHook, ReadFile: func(string) ([]byte, error) {
+			return []byte(content), nil
+		}}
+		err := f.UnmarshalText([]byte(name))
+
+		return f.Data, err
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		got, err := load("ids.yaml", "id: 9007199254740993\nmax: 9223372036854775807\nmin: -9223372036854775808\n"+
+			"unsig: 9007199254740993\numax: 18446744073709551615\nratio: 9007199254740993\nrounds: 3.0\n")
+		require.NoError(t, err)
+
+		assert.Equal(t, ids{
+			ID:     9007199254740993,
+			Max:    math.MaxInt64,
+			Min:    math.MinInt64,
+			Unsig:  9007199254740993,
+			UMax:   math.MaxUint64,
+			Ratio:  9007199254740992,
+			Rounds: 3,
+		}, got)
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		got, err := load("ids.toml", "id = 9007199254740993\nunsig = 9223372036854775807\n")
+		require.NoError(t, err)
+
+		assert.Equal(t, ids{ID: 9007199254740993, Unsig: math.MaxInt64}, got)
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		tests := map[string]string{
+			"id: 9223372036854775808\n": "value 9223372036854775808 does not fit int64 at ID",
+			"unsig: -1\n":               "value -1 does not fit uint64 at Unsig",
+			"id: 9.3e18\n":              "value 9.3e+18 does not fit int64 at ID",
+			"umax: 1.9e19\n":            "value 1.9e+19 does not fit uint64 at UMax",
+			"rounds: 1.5\n":             "value 1.5 does not fit int at Rounds",
+		}
+
+		for content, msg := range tests {
+			_, err := load("ids.yaml", content)
+			assert.ErrorContains(t, err, msg, content)
+		}
+	})
+}
+
+func TestDecodeHookTextUnmarshaler(t *testing.T) {
+	type limits struct {
+		Size    ByteSize      `json:"size" yaml:"size" toml:"size"`
+		Max     *ByteSize     `json:"max" yaml:"max" toml:"max"`
+		Window  Duration      `json:"window" yaml:"window" toml:"window"`
+		Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	}
+
+	files := map[string]string{
+		"limits.json": `{"size": "10MB", "max": "1KiB", "window": "2s", "timeout": "5s"}`,
+		"limits.yaml": "size: 10MB\nmax: 1KiB\nwindow: 2s\ntimeout: 5s\n",
+		"limits.toml": "size = \"10MB\"\nmax = \"1KiB\"\nwindow = \"2s\"\ntimeout = \"5s\"\n",
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		return []byte(files[name]), nil
+	}
+
+	maxSize := ByteSize(1024)
+	expected := limits{Size: 10_000_000, Max: &maxSize, Window: Duration(2 * time.Second), Timeout: 5 * time.Second}
+
+	for name := range files {
+		t.Run(name, func(t *testing.T) {
+			f := File[limits]{DecodeHook: StringToDurationHook, ReadFile: readFile}
+			require.NoError(t, f.UnmarshalText([]byte(name)))
+			assert.Equal(t, expected, f.Data)
+		})
+	}
+
+	t.Run("yaml file", func(t *testing.T) {
+		f := YAMLFile[limits]{DecodeHook: StringToDurationHook, ReadFile: readFile}
+		require.NoError(t, f.UnmarshalText([]byte("limits.yaml")))
+		assert.Equal(t, expected, f.Data)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		f := JSONFile[limits]{DecodeHook: StringToDurationHook, ReadFile: func(string) ([]byte, error) {
+			return []byte(`{"size": "10 parsecs"}`), nil
+		}}
+
+		assert.ErrorContains(t, f.UnmarshalText([]byte("limits.json")), "cannot decode configtype.ByteSize at Size")
+	})
+}
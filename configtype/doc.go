@@ -1,12 +1,12 @@
 // Package configtype provides a flexible and type-safe way to load and manage configuration
-// from various sources. It supports multiple configuration formats including JSON, YAML, and TOML,
+// from various sources. It supports multiple configuration formats including JSON, YAML, TOML and XML,
 // with built-in environment variable expansion support.
 //
 // The package implements the encoding.TextUnmarshaler interface to allow configuration loading
 // from environment variables, making it easy to integrate with various configuration management systems.
 //
 // Key features:
-//   - Support for multiple configuration formats (JSON, YAML, TOML)
+//   - Support for multiple configuration formats (JSON, YAML, TOML, XML)
 //   - Environment variable expansion in both file paths and configuration content
 //   - Generic type support for type-safe configuration loading
 //   - Base64 encoding support for sensitive data
@@ -46,11 +46,37 @@
 //   - JSONFile[T]: For loading JSON configuration files
 //   - YAMLFile[T]: For loading YAML configuration files
 //   - TOMLFile[T]: For loading TOML configuration files
-//   - Base64: For handling base64-encoded configuration values
+//   - XMLFile[T]: For loading XML configuration files (expanded values are XML-escaped)
+//   - File[T]: For loading a file whose format is chosen by its extension, optionally in strict mode
+//     or with a DecodeHook converting values, e.g. StringToDurationHook, StringToIPHook, StringToTimeHook, TextUnmarshalerHook
+//     and a Profile merged over the base document
+//   - DynamicFile[T]: Like File[T], but the format is named by an environment variable (CONFIG_FORMAT by default)
+//   - EnvFile[T]: For loading a dotenv file into a struct with the goconfig conventions, the environment overriding its entries
+//   - ReadOnly[T]: Like File[T], but Get returns a deep copy so callers cannot mutate the loaded data
+//   - MultiFile[T]: For merging an ordered list of files in any supported format, later files winning
+//   - Base64: For base64-encoded values in the standard, raw, URL or raw URL encoding
+//   - StrictBase64: For base64-encoded values in the standard encoding only
+//   - Duration: For durations such as "5s" in env and file configs
+//   - HumanDuration: For durations that also accept days and weeks, such as "1d12h" or "2w"
+//   - ByteSize: For human-readable sizes such as "10MiB" in env and file configs
+//   - PEMCertificate, PEMPrivateKey: For TLS material given inline as PEM or as a path to a PEM file
+//   - SecretMap: For a JSON object of secrets in a single variable, with Load to route entries into struct fields
+//   - Set[T]: For sets read from a separated list, with Has for O(1) membership checks
+//   - CompressedPayload[T]: For a base64 encoded, gzipped JSON, YAML or TOML document given inline
 //
-// Each file-based configuration type supports:
-//   - Environment variable expansion in file paths
+// Each file-based configuration type implements ConfigFile[T] and supports:
+//   - Environment variable expansion in file paths, with ${VAR:-default} fallbacks
 //   - Environment variable expansion in configuration content
 //   - Hot reloading via the Reload() method
+//   - Change-aware reloading via ReloadIfChanged() and StartPolling(), debounced with WithReloadDebounce
+//   - Reloading on SIGHUP (or other signals) via ReloadOnSignal() with OnReload() callbacks
 //   - Type-safe configuration loading through generics
+//   - A ReadFile field to read content from somewhere other than the disk
+//   - MarshalText and MarshalJSON returning FilePath, or Data when MarshalData is set
+//
+// LoadAndValidate loads a file with File[T] in strict mode and runs its Validate method,
+// reporting unknown keys and validation problems together.
+//
+// ApplyEnvOverrides loads environment variables into the Data of a loaded file,
+// so file values act as defaults that the environment can override.
 package configtype
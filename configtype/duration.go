@@ -0,0 +1,65 @@
+package configtype
+
+import (
+	"encoding"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*Duration)(nil)
+	_ encoding.TextMarshaler   = Duration(0)
+)
+
+// Duration is a time.Duration that can be read from text such as "5s" or "1h30m".
+// It implements encoding.TextUnmarshaler, so the same value can be loaded from
+// environment variables and from JSON, YAML or TOML configuration files.
+//
+// Example usage:
+//
+//	type ServerConfig struct {
+//		Timeout configtype.Duration `yaml:"timeout"`
+//	}
+//
+//	// server.yaml
+//	// timeout: 5s
+//
+//	var server configtype.YAMLFile[ServerConfig]
+//	if err := server.UnmarshalText([]byte("server.yaml")); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	fmt.Println(server.Data.Timeout.Duration()) // 5s
+type Duration time.Duration
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It parses the text with time.ParseDuration.
+func (d *Duration) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	v, err := time.ParseDuration(string(data))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse duration")
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Duration returns the value as a time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the duration formatted like time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
@@ -0,0 +1,111 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedValuesConfig struct {
+	Timeout Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	MaxSize ByteSize `json:"max_size" yaml:"max_size" toml:"max_size"`
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "seconds", input: "5s", expected: 5 * time.Second},
+		{name: "mixed units", input: "1h30m", expected: 90 * time.Minute},
+		{name: "negative", input: "-2s", expected: -2 * time.Second},
+		{name: "empty input", input: "", expected: 0},
+		{name: "missing unit", input: "5", wantErr: true},
+		{name: "invalid", input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Duration
+			err := d.UnmarshalText([]byte(tt.input))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, d.Duration())
+		})
+	}
+}
+
+func TestTypedValuesInFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	expected := typedValuesConfig{
+		Timeout: Duration(5 * time.Second),
+		MaxSize: 10 * MiB,
+	}
+
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var f JSONFile[typedValuesConfig]
+		path := write("typed.json", `{"timeout": "5s", "max_size": "10MiB"}`)
+		require.NoError(t, f.UnmarshalText([]byte(path)))
+		assert.Equal(t, expected, f.Data)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var f YAMLFile[typedValuesConfig]
+		path := write("typed.yaml", "timeout: 5s\nmax_size: 10MiB\n")
+		require.NoError(t, f.UnmarshalText([]byte(path)))
+		assert.Equal(t, expected, f.Data)
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		var f TOMLFile[typedValuesConfig]
+		path := write("typed.toml", "timeout = \"5s\"\nmax_size = \"10MiB\"\n")
+		require.NoError(t, f.UnmarshalText([]byte(path)))
+		assert.Equal(t, expected, f.Data)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("TIMEOUT", "5s")
+		t.Setenv("MAX_SIZE", "10MiB")
+
+		var cfg typedValuesConfig
+		require.NoError(t, goconfig.Load(&cfg))
+		assert.Equal(t, expected, cfg)
+	})
+}
+
+func TestTypedMapValues(t *testing.T) {
+	type Config struct {
+		Quotas   map[string]ByteSize `format:"kv"`
+		Timeouts map[string]Duration
+	}
+
+	t.Setenv("QUOTAS", "uploads=10MiB,logs=1GB")
+	t.Setenv("TIMEOUTS", `{"read": "5s", "write": "1m"}`)
+
+	var cfg Config
+	require.NoError(t, goconfig.Load(&cfg))
+
+	assert.Equal(t, map[string]ByteSize{"uploads": 10 * MiB, "logs": GB}, cfg.Quotas)
+	assert.Equal(t, map[string]Duration{
+		"read":  Duration(5 * time.Second),
+		"write": Duration(time.Minute),
+	}, cfg.Timeouts)
+}
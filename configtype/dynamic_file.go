@@ -0,0 +1,148 @@
+package configtype
+
+import (
+	"encoding"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*DynamicFile[any])(nil)
+	_ ConfigFile[any]          = (*DynamicFile[any])(nil)
+)
+
+// DefaultFormatEnv is the environment variable read by DynamicFile for the
+// format of the file when FormatEnv is empty.
+const DefaultFormatEnv = "CONFIG_FORMAT"
+
+// DynamicFile represents a configuration file whose format is named by an
+// environment variable rather than chosen by the file extension, so both the
+// path and the format can be templated by the platform, e.g. a secret mounted
+// at a path without extension.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+//
+// The format is read from the variable named by FormatEnv when the file is
+// loaded: "json", "yaml" (or "yml"), "toml" or "xml", ignoring case. When the
+// variable is unset or empty, the Format field is used, and then the extension
+// of the path.
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//		// export APP_CONFIG=/run/secrets/app
+//		// export APP_CONFIG_FORMAT=yaml
+//		App configtype.DynamicFile[Settings] `env:"APP_CONFIG"`
+//	}
+//
+//	cfg := AppConfig{App: configtype.DynamicFile[Settings]{FormatEnv: "APP_CONFIG_FORMAT"}}
+//	err := goconfig.Load(&cfg)
+type DynamicFile[T any] struct {
+	// FilePath is the path to the configuration file
+	FilePath string
+	// FormatEnv names the environment variable holding the format and defaults
+	// to DefaultFormatEnv when empty. It must be set before the file is loaded.
+	FormatEnv string
+	// Format is the format used when the FormatEnv variable is unset or empty.
+	// It must be set before the file is loaded.
+	Format string
+	// Data contains the parsed configuration data
+	Data T
+	// Strict makes decoding return ErrUnknownField errors, see File.
+	// It must be set before the file is loaded.
+	Strict bool
+	// DecodeHook is called for every value while the file is decoded, see File.
+	// It must be set before the file is loaded.
+	DecodeHook DecodeHookFunc
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It must be set before the file is loaded.
+	ReadFile func(name string) ([]byte, error)
+	// ErrorSnippet adds the lines around a parse error to the error message, see File.
+	// It must be set before the file is loaded.
+	ErrorSnippet bool
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It reads the file path from the provided text and loads the configuration
+// in the format named by the FormatEnv variable.
+// The file path can contain environment variables that will be expanded.
+func (d *DynamicFile[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	d.FilePath = string(data)
+
+	return d.parseFile()
+}
+
+// parseFile resolves the format, then reads and decodes the file. Data is
+// replaced only when the file was loaded, which like for File includes an
+// ErrUnknownField error in strict mode.
+func (d *DynamicFile[T]) parseFile() error {
+	format, err := d.resolveFormat()
+	if err != nil {
+		return err
+	}
+
+	f := File[T]{
+		FilePath:     d.FilePath,
+		Data:         d.Data,
+		Strict:       d.Strict,
+		DecodeHook:   d.DecodeHook,
+		ReadFile:     d.ReadFile,
+		ErrorSnippet: d.ErrorSnippet,
+		format:       format,
+	}
+
+	err = f.parseFile()
+	if err != nil && !errors.Is(err, ErrUnknownField) {
+		return err
+	}
+
+	d.Data = f.Data
+
+	return err
+}
+
+// resolveFormat returns the format named by the FormatEnv variable, or Format,
+// or an empty string to use the file extension.
+func (d *DynamicFile[T]) resolveFormat() (string, error) {
+	env := d.FormatEnv
+	if env == "" {
+		env = DefaultFormatEnv
+	}
+
+	if name := os.Getenv(env); name != "" {
+		if _, err := formatNamed(name); err != nil {
+			return "", errors.Wrapf(err, "invalid %s", env)
+		}
+
+		return name, nil
+	}
+
+	if d.Format == "" {
+		return "", nil
+	}
+
+	if _, err := formatNamed(d.Format); err != nil {
+		return "", err
+	}
+
+	return d.Format, nil
+}
+
+// Reload reads the format variable and the configuration file again.
+// If no file path is set, it returns nil without doing anything.
+func (d *DynamicFile[T]) Reload() error {
+	if d.FilePath == "" {
+		return nil
+	}
+
+	return d.parseFile()
+}
+
+// Get returns the parsed configuration data.
+func (d *DynamicFile[T]) Get() T {
+	return d.Data
+}
@@ -0,0 +1,112 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicFile(t *testing.T) {
+	type settings struct {
+		Name string `json:"name" yaml:"name" toml:"name" xml:"name"`
+	}
+
+	contents := map[string]string{
+		"/run/secrets/json": `{"name": "json"}`,
+		"/run/secrets/yaml": "name: yaml\n",
+		"/run/secrets/toml": "name = \"toml\"\n",
+		"/run/secrets/xml":  "<settings><name>xml</name></settings>",
+		"/etc/app.yml":      "name: extension\n",
+	}
+
+	read := func(name string) ([]byte, error) {
+		return []byte(contents[name]), nil
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		env    string
+		format string
+		want   string
+		err    string
+	}{
+		{name: "json", path: "/run/secrets/json", env: "json", want: "json"},
+		{name: "yaml", path: "/run/secrets/yaml", env: "YAML", want: "yaml"},
+		{name: "toml", path: "/run/secrets/toml", env: "toml", want: "toml"},
+		{name: "xml", path: "/run/secrets/xml", env: ".xml", want: "xml"},
+		{name: "format overrides extension", path: "/etc/app.yml", env: "json", err: "failed to parse config file"},
+		{name: "format field", path: "/run/secrets/toml", format: "toml", want: "toml"},
+		{name: "env overrides format field", path: "/run/secrets/yaml", env: "yaml", format: "toml", want: "yaml"},
+		{name: "extension", path: "/etc/app.yml", want: "extension"},
+		{name: "no format", path: "/run/secrets/json", err: `unsupported config file extension ""`},
+		{name: "unknown env format", path: "/run/secrets/json", env: "ini", err: `invalid DF_FORMAT: unsupported config format "ini"`},
+		{name: "unknown format field", path: "/run/secrets/json", format: "hcl", err: `unsupported config format "hcl"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DF_FORMAT", tt.env)
+
+			f := DynamicFile[settings]{FormatEnv: "DF_FORMAT", Format: tt.format, ReadFile: read}
+
+			err := f.UnmarshalText([]byte(tt.path))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				assert.Zero(t, f.Data)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.Get().Name)
+		})
+	}
+}
+
+func TestDynamicFileStrict(t *testing.T) {
+	type settings struct {
+		Name string `yaml:"name"`
+	}
+
+	t.Setenv("DF_STRICT_FORMAT", "yaml")
+
+	f := DynamicFile[settings]{FormatEnv: "DF_STRICT_FORMAT", Strict: true, ReadFile: func(string) ([]byte, error) {
+		return []byte("name: api\nnmae: typo\n"), nil
+	}}
+
+	err := f.UnmarshalText([]byte("/run/secrets/app"))
+
+	assert.ErrorIs(t, err, ErrUnknownField)
+	assert.ErrorContains(t, err, "field nmae not found")
+	assert.Equal(t, "api", f.Get().Name)
+}
+
+func TestDynamicFileLoad(t *testing.T) {
+	type settings struct {
+		Name string `yaml:"name"`
+	}
+
+	type config struct {
+		App DynamicFile[settings]
+	}
+
+	path := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.WriteFile(path, []byte("name: ${DF_NAME}\n"), 0o600))
+
+	t.Setenv("DF_LOAD_APP", path)
+	t.Setenv(DefaultFormatEnv, "yaml")
+	t.Setenv("DF_NAME", "loaded")
+
+	var cfg config
+	require.NoError(t, goconfig.New(goconfig.WithPrefix("DF_LOAD")).Load(&cfg))
+	assert.Equal(t, "loaded", cfg.App.Data.Name)
+
+	t.Setenv("DF_NAME", "reloaded")
+	require.NoError(t, cfg.App.Reload())
+	assert.Equal(t, "reloaded", cfg.App.Get().Name)
+}
@@ -0,0 +1,219 @@
+package configtype
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/jkaveri/goconfig/internal/dotenv"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*EnvFile[any])(nil)
+	_ ConfigFile[any]          = (*EnvFile[any])(nil)
+	_ goconfig.KVSource        = envFileSource(nil)
+)
+
+// EnvFile represents a dotenv file made of KEY=VALUE lines, loaded into a typed struct.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// The entries of the file are read with the goconfig conventions, as if they were
+// environment variables: field names are transformed into keys, nested struct fields
+// are joined with the separator and Options such as goconfig.WithPrefix apply.
+// A variable set in the environment overrides the entry of the file.
+// The file has the syntax of goconfig.LoadFile, e.g. trailing " # comments"
+// of unquoted values are ignored.
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//		Port int
+//		DB   struct {
+//			Host string
+//			Port int
+//		}
+//	}
+//
+//	// The file at /etc/app/app.env contains:
+//	// PORT=8080
+//	// DB_HOST=${DB_HOST:-localhost}
+//	// DB_PORT=5432
+//
+//	type Config struct {
+//		App configtype.EnvFile[AppConfig] `env:"APP_ENV_FILE"`
+//	}
+//
+//	// export APP_ENV_FILE=/etc/app/app.env
+//	var config Config
+//	if err := goconfig.Load(&config); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	fmt.Println(config.App.Data.DB.Host)
+type EnvFile[T any] struct {
+	// FilePath is the path to the dotenv file
+	FilePath string
+	// Data contains the loaded configuration data
+	Data T
+	// Options configure the goconfig Loader reading the entries into Data,
+	// e.g. goconfig.WithPrefix("APP") to read APP_PORT into the Port field.
+	// They must be set before the file is loaded.
+	Options []goconfig.Option
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It allows tests and virtual filesystems to supply content without touching disk.
+	// It must be set before the file is loaded.
+	ReadFile func(name string) ([]byte, error)
+
+	// sum is the checksum of the last loaded content
+	sum [sha256.Size]byte
+	// onReload contains the callbacks registered with OnReload
+	onReload []func(err error)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It reads the dotenv file path from the provided text and loads the configuration.
+// The file path can contain environment variables that will be expanded.
+func (f *EnvFile[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	f.FilePath = string(data)
+
+	return f.parse()
+}
+
+// parse reads the dotenv file and loads its entries into Data.
+func (f *EnvFile[T]) parse() error {
+	content, err := f.readEnvFile()
+	if err != nil {
+		return err
+	}
+
+	return f.load(content)
+}
+
+// readEnvFile reads the dotenv file and expands environment variables
+// in the file path and file content.
+func (f *EnvFile[T]) readEnvFile() (string, error) {
+	expandedPath := expandEnv(f.FilePath)
+
+	content, err := readFile(f.ReadFile, expandedPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot load env file: %s", expandedPath)
+	}
+
+	return expandEnv(string(content)), nil
+}
+
+// load parses the expanded content and runs a goconfig Loader over a new T
+// with the entries as its source, so entries removed from the file are reset
+// on reload. The environment takes precedence over the entries. Data and the
+// checksum are only replaced once the content was loaded.
+func (f *EnvFile[T]) load(content string) error {
+	entries, err := dotenv.Parse(strings.NewReader(content), dotenv.WithCommentStrip())
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse env file: %s", f.FilePath)
+	}
+
+	opts := append(slices.Clone(f.Options), goconfig.WithKVSource(envFileSource(entries), goconfig.PreferEnv))
+
+	var data T
+	if err := goconfig.New(opts...).Load(&data); err != nil {
+		return errors.Wrapf(err, "failed to load env file: %s", f.FilePath)
+	}
+
+	f.Data = data
+	f.sum = checksum(content)
+
+	return nil
+}
+
+// Reload reloads the dotenv file.
+// It is useful when the file has been modified and needs to be reloaded.
+// If no file path is set, it returns nil without doing anything.
+func (f *EnvFile[T]) Reload() error {
+	if f.FilePath == "" {
+		return nil
+	}
+
+	return f.parse()
+}
+
+// Get returns the loaded configuration data.
+func (f *EnvFile[T]) Get() T {
+	return f.Data
+}
+
+// ReloadIfChanged reloads the dotenv file only when its content
+// (after environment variable expansion) changed since the last load.
+// It reports whether the content changed. When the content fails to load, the
+// previous Data is kept and the content is loaded again by the next call.
+func (f *EnvFile[T]) ReloadIfChanged() (bool, error) {
+	if f.FilePath == "" {
+		return false, nil
+	}
+
+	content, err := f.readEnvFile()
+	if err != nil {
+		return false, err
+	}
+
+	sum := checksum(content)
+	if sum == f.sum {
+		return false, nil
+	}
+
+	return true, f.load(content)
+}
+
+// StartPolling checks the file every interval until ctx is done and calls
+// ReloadIfChanged once a change has settled, see JSONFile.StartPolling.
+// The returned channel is closed once polling has stopped.
+func (f *EnvFile[T]) StartPolling(
+	ctx context.Context,
+	interval time.Duration,
+	onChange func(err error),
+	opts ...PollOption,
+) <-chan struct{} {
+	return poll(ctx, f, onChange, newPollConfig(interval, opts))
+}
+
+// peek reads the file and reports the checksum of its content and whether it
+// differs from the loaded content, without loading it.
+func (f *EnvFile[T]) peek() ([sha256.Size]byte, bool, error) {
+	content, err := f.readEnvFile()
+	if err != nil {
+		return [sha256.Size]byte{}, false, err
+	}
+
+	sum := checksum(content)
+
+	return sum, sum != f.sum, nil
+}
+
+// OnReload registers fn to be called by ReloadOnSignal after a reload changed
+// the content (with a nil error) or failed (with the error).
+// Callbacks must be registered before ReloadOnSignal is called.
+func (f *EnvFile[T]) OnReload(fn func(err error)) {
+	f.onReload = append(f.onReload, fn)
+}
+
+// ReloadOnSignal calls ReloadIfChanged every time one of sig is received until ctx is done,
+// see JSONFile.ReloadOnSignal. The returned channel is closed once it has stopped.
+func (f *EnvFile[T]) ReloadOnSignal(ctx context.Context, sig ...os.Signal) <-chan struct{} {
+	return reloadOnSignal(ctx, sig, f.ReloadIfChanged, f.onReload)
+}
+
+// envFileSource adapts the entries of a dotenv file to goconfig.KVSource.
+type envFileSource map[string]string
+
+func (s envFileSource) Get(key string) (string, bool, error) {
+	v, ok := s[key]
+	return v, ok, nil
+}
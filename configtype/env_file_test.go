@@ -0,0 +1,138 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type envFileConfig struct {
+	Port int
+	Tags []string
+	DB   struct {
+		Host string
+		Port int
+	}
+}
+
+func TestEnvFile(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "app.env")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	newFile := func() *EnvFile[envFileConfig] {
+		return &EnvFile[envFileConfig]{Options: []goconfig.Option{goconfig.WithPrefix("ENVFILE")}}
+	}
+
+	t.Run("nested fields", func(t *testing.T) {
+		path := write(t, "# app\nENVFILE_PORT=8080\nENVFILE_TAGS=a,b\nENVFILE_DB_HOST=db.local\nENVFILE_DB_PORT='5432'\n")
+
+		f := newFile()
+		require.NoError(t, f.UnmarshalText([]byte(path)))
+
+		assert.Equal(t, 8080, f.Get().Port)
+		assert.Equal(t, []string{"a", "b"}, f.Get().Tags)
+		assert.Equal(t, "db.local", f.Get().DB.Host)
+		assert.Equal(t, 5432, f.Get().DB.Port)
+	})
+
+	t.Run("inline comments", func(t *testing.T) {
+		path := write(t, "ENVFILE_PORT=8080 # http\nENVFILE_DB_HOST=\"db # local\"\nexport ENVFILE_DB_PORT=5432 # postgres\n")
+
+		f := newFile()
+		require.NoError(t, f.UnmarshalText([]byte(path)))
+
+		assert.Equal(t, 8080, f.Data.Port)
+		assert.Equal(t, "db # local", f.Data.DB.Host, "quoted values keep #")
+		assert.Equal(t, 5432, f.Data.DB.Port)
+	})
+
+	t.Run("env overrides entries", func(t *testing.T) {
+		path := write(t, "ENVFILE_DB_HOST=db.local\nENVFILE_DB_PORT=5432\n")
+		t.Setenv("ENVFILE_DB_HOST", "db.prod")
+
+		f := newFile()
+		require.NoError(t, f.UnmarshalText([]byte(path)))
+
+		assert.Equal(t, "db.prod", f.Data.DB.Host)
+		assert.Equal(t, 5432, f.Data.DB.Port)
+	})
+
+	t.Run("env expansion", func(t *testing.T) {
+		dir := filepath.Dir(write(t, "ENVFILE_DB_HOST=${ENVFILE_TEST_HOST:-localhost}\nENVFILE_PORT=${ENVFILE_TEST_PORT}\n"))
+		t.Setenv("ENVFILE_TEST_DIR", dir)
+		t.Setenv("ENVFILE_TEST_PORT", "9090")
+
+		f := newFile()
+		require.NoError(t, f.UnmarshalText([]byte("${ENVFILE_TEST_DIR}/app.env")))
+
+		assert.Equal(t, "localhost", f.Data.DB.Host)
+		assert.Equal(t, 9090, f.Data.Port)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		path := write(t, "ENVFILE_PORT=8080\nENVFILE_DB_HOST=db.local\n")
+
+		f := newFile()
+		require.NoError(t, f.UnmarshalText([]byte(path)))
+
+		changed, err := f.ReloadIfChanged()
+		require.NoError(t, err)
+		assert.False(t, changed)
+
+		require.NoError(t, os.WriteFile(path, []byte("ENVFILE_PORT=9090\n"), 0o600))
+		require.NoError(t, f.Reload())
+
+		assert.Equal(t, 9090, f.Data.Port)
+		// entries removed from the file are reset
+		assert.Empty(t, f.Data.DB.Host)
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		type App struct {
+			Server struct {
+				EnvFileTestPort int
+			}
+		}
+
+		type Config struct {
+			App EnvFile[App] `env:"ENVFILE_TEST_PATH"`
+		}
+
+		t.Setenv("ENVFILE_TEST_PATH", write(t, "SERVER_ENV_FILE_TEST_PORT=7070\n"))
+
+		var cfg Config
+		require.NoError(t, goconfig.Load(&cfg))
+		assert.Equal(t, 7070, cfg.App.Data.Server.EnvFileTestPort)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		f := newFile()
+		assert.NoError(t, f.UnmarshalText(nil))
+		assert.NoError(t, f.Reload())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		f := newFile()
+		assert.ErrorContains(t, f.UnmarshalText([]byte("/nonexistent/app.env")), "cannot load env file")
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		f := newFile()
+		assert.ErrorContains(t, f.UnmarshalText([]byte(write(t, "ENVFILE_PORT\n"))), "failed to parse env file")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		f := newFile()
+		assert.ErrorContains(t, f.UnmarshalText([]byte(write(t, "ENVFILE_PORT=abc\n"))), "failed to load env file")
+	})
+}
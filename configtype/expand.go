@@ -0,0 +1,36 @@
+package configtype
+
+import (
+	"os"
+	"strings"
+)
+
+// expandEnv replaces $var and ${var} in s like os.ExpandEnv and also supports defaults:
+//   - ${var:-default} uses default when var is unset or empty
+//   - ${var-default} uses default only when var is unset
+//
+// Defaults are used as-is; nested references such as ${A:-${B}} are not supported.
+func expandEnv(s string) string {
+	return os.Expand(s, envWithDefault)
+}
+
+// envWithDefault resolves a variable reference of the form name, name:-default or name-default.
+func envWithDefault(ref string) string {
+	if name, def, ok := strings.Cut(ref, ":-"); ok {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+
+		return def
+	}
+
+	if name, def, ok := strings.Cut(ref, "-"); ok {
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+
+		return def
+	}
+
+	return os.Getenv(ref)
+}
@@ -0,0 +1,63 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TEST_EXPAND_SET", "value")
+	t.Setenv("TEST_EXPAND_EMPTY", "")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "$TEST_EXPAND_SET", expected: "value"},
+		{input: "${TEST_EXPAND_SET}", expected: "value"},
+		{input: "${TEST_EXPAND_SET:-fallback}", expected: "value"},
+		{input: "${TEST_EXPAND_EMPTY:-fallback}", expected: "fallback"},
+		{input: "${TEST_EXPAND_UNSET:-fallback}", expected: "fallback"},
+		{input: "${TEST_EXPAND_EMPTY-fallback}", expected: ""},
+		{input: "${TEST_EXPAND_UNSET-fallback}", expected: "fallback"},
+		{input: "${TEST_EXPAND_UNSET:-/etc/app/config.yaml}", expected: "/etc/app/config.yaml"},
+		{input: "$TEST_EXPAND_UNSET", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expandEnv(tt.input))
+		})
+	}
+}
+
+func TestFilePathDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	defaultPath := filepath.Join(tmpDir, "default.yaml")
+	require.NoError(t, os.WriteFile(defaultPath, []byte("name: default"), 0o644))
+
+	customPath := filepath.Join(tmpDir, "custom.yaml")
+	require.NoError(t, os.WriteFile(customPath, []byte("name: custom"), 0o644))
+
+	filePath := "${TEST_CONFIG_PATH:-" + defaultPath + "}"
+
+	t.Run("absent", func(t *testing.T) {
+		var config YAMLFile[TestYAMLConfig]
+		require.NoError(t, config.UnmarshalText([]byte(filePath)))
+		assert.Equal(t, "default", config.Data.Name)
+	})
+
+	t.Run("present", func(t *testing.T) {
+		t.Setenv("TEST_CONFIG_PATH", customPath)
+
+		var config JSONFile[TestConfig]
+		require.NoError(t, os.WriteFile(customPath, []byte(`{"name": "custom"}`), 0o644))
+		require.NoError(t, config.UnmarshalText([]byte(filePath)))
+		assert.Equal(t, "custom", config.Data.Name)
+	})
+}
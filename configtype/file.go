@@ -0,0 +1,443 @@
+package configtype
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*File[any])(nil)
+	_ ConfigFile[any]          = (*File[any])(nil)
+	_ encoding.TextMarshaler   = File[any]{}
+	_ json.Marshaler           = File[any]{}
+)
+
+// ErrUnknownField is returned by strict decoding for keys of a file that do not
+// match any field of the data.
+var ErrUnknownField = errors.New("unknown field")
+
+// File represents a configuration file whose format is chosen by the extension
+// of its path: .json, .yaml or .yml, .toml and .xml.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// The generic type T specifies the type of the configuration data.
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//		// export APP_CONFIG=/etc/app/config.yaml, or config.toml, config.json...
+//		App configtype.File[Settings] `env:"APP_CONFIG"`
+//	}
+type File[T any] struct {
+	// FilePath is the path to the configuration file
+	FilePath string
+	// Data contains the parsed configuration data
+	Data T
+	// Strict makes decoding return ErrUnknownField errors for keys that do not
+	// match any field of Data. Known fields are still decoded. XML files are
+	// never checked. It must be set before the file is loaded.
+	Strict bool
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It allows tests and virtual filesystems to supply content without touching disk.
+	// It must be set before the file is loaded.
+	ReadFile func(name string) ([]byte, error)
+	// DecodeHook, when set, is called for every value while the decoded file is
+	// converted into Data, e.g. StringToDurationHook to read "5s" into a
+	// time.Duration. Decode hooks are not supported for XML files.
+	// It must be set before the file is loaded.
+	DecodeHook DecodeHookFunc
+	// MarshalData makes MarshalText and MarshalJSON encode Data instead of FilePath,
+	// e.g. to print the effective configuration rather than where it came from.
+	MarshalData bool
+	// ErrorSnippet adds the lines of the expanded content around a parse error
+	// to the error message. The position is reported regardless; the snippet is
+	// opt-in since expanded content may contain secrets.
+	ErrorSnippet bool
+
+	// Profile selects the profile merged over the rest of the file: its subtree
+	// under ProfilesKey overrides the base, maps key by key while lists and
+	// scalars are replaced. The profiles key itself is not decoded into Data.
+	// XML files do not support profiles. It must be set before the file is loaded.
+	Profile string
+	// ProfileEnv names an environment variable holding the profile, which takes
+	// precedence over Profile when set and not empty.
+	// It must be set before the file is loaded.
+	ProfileEnv string
+	// ProfilesKey is the top-level key holding the profiles and defaults to
+	// DefaultProfilesKey when empty. It must be set before the file is loaded.
+	ProfilesKey string
+
+	// format names the format of the content, overriding the file extension
+	format string
+	// sum is the checksum of the last loaded content
+	sum [sha256.Size]byte
+	// onReload contains the callbacks registered with OnReload
+	onReload []func(err error)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It reads the file path from the provided text and loads the configuration.
+// The file path can contain environment variables that will be expanded.
+func (f *File[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	f.FilePath = string(data)
+
+	return f.parseFile()
+}
+
+// parseFile reads and parses the configuration file.
+func (f *File[T]) parseFile() error {
+	content, err := f.readFile()
+	if err != nil {
+		return err
+	}
+
+	return loadDecoded(&f.Data, &f.sum, content, f.decode)
+}
+
+// readFile reads the configuration file and expands environment variables
+// in the file path and file content.
+func (f *File[T]) readFile() (string, error) {
+	expandedPath := expandEnv(f.FilePath)
+
+	format, err := f.fileFormat(expandedPath)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := readFile(f.ReadFile, expandedPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot load config file: %s", expandedPath)
+	}
+
+	return format.expand(string(content)), nil
+}
+
+// decode decodes the expanded content into data according to the file extension.
+// With a profile, the content of the file is decoded first, so syntax errors
+// are reported with positions in the file, and the merged document after.
+func (f *File[T]) decode(content string, data *T) error {
+	expandedPath := expandEnv(f.FilePath)
+
+	format, err := f.fileFormat(expandedPath)
+	if err != nil {
+		return err
+	}
+
+	profile := f.activeProfile()
+	if profile == "" {
+		unknown, err := f.decodeContent(content, format, data)
+		if err != nil {
+			err = newParseError(err, content, f.ErrorSnippet)
+			return errors.Wrapf(err, "failed to parse config file: %s", expandedPath)
+		}
+
+		return errors.Wrapf(unknown, "config file: %s", expandedPath)
+	}
+
+	if _, err := f.decodeContent(content, format, new(T)); err != nil {
+		err = newParseError(err, content, f.ErrorSnippet)
+		return errors.Wrapf(err, "failed to parse config file: %s", expandedPath)
+	}
+
+	merged, err := applyProfile(content, format, f.profilesKey(), profile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to apply profile of config file: %s", expandedPath)
+	}
+
+	// positions in the merged document do not match the file
+	unknown, err := f.decodeContent(merged, format, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse config file: %s with profile %s", expandedPath, profile)
+	}
+
+	return errors.Wrapf(unknown, "config file: %s", expandedPath)
+}
+
+// decodeContent decodes content into data and, in strict mode, returns an
+// ErrUnknownField error for every key matching no field of data.
+func (f *File[T]) decodeContent(content string, format fileFormat, data *T) (unknown, err error) {
+	if f.DecodeHook != nil {
+		unknown, err = decodeWithHook(content, format, f.DecodeHook, data)
+	} else {
+		err = format.decode(content, data)
+	}
+
+	if err != nil || !f.Strict {
+		return nil, err
+	}
+
+	// without a hook, the keys are checked by decoding the content again
+	if f.DecodeHook == nil && format.unknownFields != nil {
+		unknown = format.unknownFields(content, data)
+	}
+
+	return unknown, nil
+}
+
+// Reload reloads the configuration file.
+// If no file path is set, it returns nil without doing anything.
+func (f *File[T]) Reload() error {
+	if f.FilePath == "" {
+		return nil
+	}
+
+	return f.parseFile()
+}
+
+// Get returns the parsed configuration data.
+func (f *File[T]) Get() T {
+	return f.Data
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns FilePath, or Data encoded in the format of the file extension
+// when MarshalData is set.
+func (f File[T]) MarshalText() ([]byte, error) {
+	if !f.MarshalData {
+		return []byte(f.FilePath), nil
+	}
+
+	format, err := f.fileFormat(expandEnv(f.FilePath))
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalText(f.FilePath, f.Data, true, format)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It returns FilePath as a JSON string, or Data when MarshalData is set.
+func (f File[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(f.FilePath, f.Data, f.MarshalData)
+}
+
+// ReloadIfChanged reloads the configuration file only when its content
+// (after environment variable expansion) changed since the last load.
+// It reports whether the content changed. When the content fails to decode, the
+// previous Data is kept and the content is loaded again by the next call.
+func (f *File[T]) ReloadIfChanged() (bool, error) {
+	if f.FilePath == "" {
+		return false, nil
+	}
+
+	content, err := f.readFile()
+	if err != nil {
+		return false, err
+	}
+
+	sum := checksum(content)
+	if sum == f.sum {
+		return false, nil
+	}
+
+	return true, loadDecoded(&f.Data, &f.sum, content, f.decode)
+}
+
+// StartPolling checks the file every interval until ctx is done and calls
+// ReloadIfChanged once a change has settled for the debounce duration (see
+// WithReloadDebounce), so a burst of writes results in a single reload.
+// onChange is called with a nil error after the content changed and was reloaded,
+// and with the error when reloading fails. Data is updated from the polling goroutine,
+// so readers must synchronize with onChange.
+// The returned channel is closed once polling has stopped.
+func (f *File[T]) StartPolling(
+	ctx context.Context,
+	interval time.Duration,
+	onChange func(err error),
+	opts ...PollOption,
+) <-chan struct{} {
+	return poll(ctx, f, onChange, newPollConfig(interval, opts))
+}
+
+// peek reads the file and reports the checksum of its content and whether it
+// differs from the loaded content, without decoding it.
+func (f *File[T]) peek() ([sha256.Size]byte, bool, error) {
+	content, err := f.readFile()
+	if err != nil {
+		return [sha256.Size]byte{}, false, err
+	}
+
+	sum := checksum(content)
+
+	return sum, sum != f.sum, nil
+}
+
+// OnReload registers fn to be called by ReloadOnSignal after a reload changed
+// the content (with a nil error) or failed (with the error).
+// Callbacks must be registered before ReloadOnSignal is called.
+func (f *File[T]) OnReload(fn func(err error)) {
+	f.onReload = append(f.onReload, fn)
+}
+
+// ReloadOnSignal calls ReloadIfChanged every time one of sig is received until ctx is done.
+// It listens for SIGHUP when no signal is given and stops handling the signals once
+// ctx is done, restoring their default behavior unless something else handles them.
+// The OnReload callbacks are invoked from the signal goroutine, so readers of Data
+// must synchronize with them. The returned channel is closed once it has stopped.
+func (f *File[T]) ReloadOnSignal(ctx context.Context, sig ...os.Signal) <-chan struct{} {
+	return reloadOnSignal(ctx, sig, f.ReloadIfChanged, f.onReload)
+}
+
+// fileFormat describes how a configuration format is expanded and decoded.
+type fileFormat struct {
+	// expand expands environment variables in the content
+	expand func(content string) string
+	// decode decodes the content into v, ignoring unknown keys
+	decode func(content string, v any) error
+	// unknownFields returns an ErrUnknownField error for each key of the content
+	// that does not match a field of v, or nil when the format cannot tell
+	unknownFields func(content string, v any) error
+	// encode encodes v in the format
+	encode func(v any) ([]byte, error)
+	// tag is the struct tag naming fields in the format, empty when the format
+	// cannot be decoded into a generic structure for decode hooks
+	tag string
+}
+
+// fileFormats maps file extensions to their format.
+var fileFormats = map[string]fileFormat{
+	".json": {
+		expand: expandEnv,
+		decode: func(content string, v any) error {
+			return json.Unmarshal([]byte(content), v)
+		},
+		unknownFields: func(content string, v any) error {
+			dec := json.NewDecoder(strings.NewReader(content))
+			dec.DisallowUnknownFields()
+
+			// encoding/json stops at the first unknown field
+			err := dec.Decode(newOf(v))
+			if err == nil {
+				return nil
+			}
+
+			if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+				return errors.Wrapf(ErrUnknownField, "key %s", name)
+			}
+
+			return err
+		},
+		encode: json.Marshal,
+		tag:    "json",
+	},
+	".yaml": yamlFormat,
+	".yml":  yamlFormat,
+	".toml": {
+		expand: expandEnv,
+		decode: func(content string, v any) error {
+			_, err := toml.Decode(content, v)
+			return err
+		},
+		unknownFields: func(content string, v any) error {
+			md, err := toml.Decode(content, newOf(v))
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, key := range md.Undecoded() {
+				errs = append(errs, errors.Wrapf(ErrUnknownField, "key %s", key))
+			}
+
+			return stderrors.Join(errs...)
+		},
+		encode: func(v any) ([]byte, error) {
+			var buf bytes.Buffer
+			err := toml.NewEncoder(&buf).Encode(v)
+
+			return buf.Bytes(), err
+		},
+		tag: "toml",
+	},
+	".xml": {
+		expand: func(content string) string {
+			return os.Expand(content, xmlEscapedEnv)
+		},
+		decode: func(content string, v any) error {
+			return xml.Unmarshal([]byte(content), v)
+		},
+		encode: xml.Marshal,
+	},
+}
+
+var yamlFormat = fileFormat{
+	expand: expandEnv,
+	decode: func(content string, v any) error {
+		return yaml.Unmarshal([]byte(content), v)
+	},
+	unknownFields: func(content string, v any) error {
+		dec := yaml.NewDecoder(strings.NewReader(content))
+		dec.KnownFields(true)
+
+		err := dec.Decode(newOf(v))
+
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return err
+		}
+
+		var errs []error
+		for _, msg := range typeErr.Errors {
+			errs = append(errs, errors.Wrap(ErrUnknownField, msg))
+		}
+
+		return stderrors.Join(errs...)
+	},
+	encode: yaml.Marshal,
+	tag:    "yaml",
+}
+
+// fileFormat returns the format set for the file, or the format of its extension.
+func (f *File[T]) fileFormat(path string) (fileFormat, error) {
+	if f.format != "" {
+		return formatNamed(f.format)
+	}
+
+	return formatOf(path)
+}
+
+// formatNamed returns the format with the given name, e.g. "yaml" or ".yaml",
+// ignoring case.
+func formatNamed(name string) (fileFormat, error) {
+	format, ok := fileFormats["."+strings.ToLower(strings.TrimPrefix(name, "."))]
+	if !ok {
+		return fileFormat{}, errors.Errorf("unsupported config format %q, expected json, yaml, yml, toml or xml", name)
+	}
+
+	return format, nil
+}
+
+// formatOf returns the format of a file from its extension.
+func formatOf(path string) (fileFormat, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	format, ok := fileFormats[ext]
+	if !ok {
+		return fileFormat{}, errors.Errorf("unsupported config file extension %q: %s", ext, path)
+	}
+
+	return format, nil
+}
+
+// newOf returns a pointer to a new zero value of the type v points to,
+// so strict decoding does not touch the decoded data.
+func newOf(v any) any {
+	return reflect.New(reflect.TypeOf(v).Elem()).Interface()
+}
@@ -0,0 +1,54 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "config.json", content: `{"name": "json", "extra": 1}`},
+		{name: "config.yaml", content: "name: yaml\nextra: 1\n"},
+		{name: "config.yml", content: "name: yml\nextra: 1\n"},
+		{name: "config.toml", content: "name = \"toml\"\nextra = 1\n"},
+		{name: "config.xml", content: `<config><name>xml</name><extra>1</extra></config>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, tt.name)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			var f File[polymorphicConfig]
+			require.NoError(t, f.UnmarshalText([]byte(path)))
+			assert.Equal(t, filepath.Ext(tt.name)[1:], f.Get().Name)
+
+			strict := File[polymorphicConfig]{Strict: true}
+			err := strict.UnmarshalText([]byte(path))
+
+			// XML has no strict mode
+			if filepath.Ext(tt.name) == ".xml" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrUnknownField)
+			assert.ErrorContains(t, err, "extra")
+			assert.Equal(t, filepath.Ext(tt.name)[1:], strict.Data.Name, "known fields are decoded")
+		})
+	}
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		var f File[polymorphicConfig]
+		assert.ErrorContains(t, f.UnmarshalText([]byte("config.ini")), `unsupported config file extension ".ini"`)
+	})
+}
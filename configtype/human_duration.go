@@ -0,0 +1,150 @@
+package configtype
+
+import (
+	"encoding"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*HumanDuration)(nil)
+	_ encoding.TextMarshaler   = HumanDuration(0)
+)
+
+const (
+	// Day is 24 hours, ignoring daylight saving time changes.
+	Day = 24 * time.Hour
+	// Week is 7 days.
+	Week = 7 * Day
+)
+
+// HumanDuration is a time.Duration that also understands days and weeks,
+// e.g. "1d", "2w" or "1d12h". A day ("d") is always 24 hours and a week ("w")
+// is always 7 days; calendar and daylight saving time changes are ignored.
+// All units of time.ParseDuration are supported as well.
+//
+// Example usage:
+//
+//	type RetentionConfig struct {
+//		KeepFor configtype.HumanDuration `env:"KEEP_FOR"`
+//	}
+//
+//	// export KEEP_FOR=2w
+type HumanDuration time.Duration
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It parses the text with ParseHumanDuration.
+func (d *HumanDuration) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	v, err := ParseHumanDuration(string(data))
+	if err != nil {
+		return err
+	}
+
+	*d = HumanDuration(v)
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The value is formatted like time.Duration, e.g. "36h0m0s" for "1d12h".
+func (d HumanDuration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Duration returns the value as a time.Duration.
+func (d HumanDuration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the duration formatted like time.Duration.
+func (d HumanDuration) String() string {
+	return time.Duration(d).String()
+}
+
+// ParseHumanDuration parses a duration like time.ParseDuration and additionally
+// accepts the units "d" (24h) and "w" (7d), which can be mixed with the others,
+// e.g. "1w2d", "1d12h" or "1.5d".
+func ParseHumanDuration(s string) (time.Duration, error) {
+	orig := s
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	if s == "0" {
+		return 0, nil
+	}
+
+	if s == "" {
+		return 0, errors.Errorf("invalid duration %q", orig)
+	}
+
+	var total time.Duration
+
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return 0, errors.Errorf("invalid duration %q", orig)
+		}
+
+		j := strings.IndexFunc(s[i:], func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' })
+		if j < 0 {
+			j = len(s) - i
+		}
+
+		num, unit := s[:i], s[i:i+j]
+		s = s[i+j:]
+
+		v, err := parseHumanUnit(num, unit)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid duration %q", orig)
+		}
+
+		if total > math.MaxInt64-v {
+			return 0, errors.Errorf("invalid duration %q: overflow", orig)
+		}
+
+		total += v
+	}
+
+	if neg {
+		return -total, nil
+	}
+
+	return total, nil
+}
+
+// parseHumanUnit converts a single number and unit pair to a duration.
+func parseHumanUnit(num, unit string) (time.Duration, error) {
+	var scale time.Duration
+
+	switch unit {
+	case "d":
+		scale = Day
+	case "w":
+		scale = Week
+	default:
+		return time.ParseDuration(num + unit)
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid number %q", num)
+	}
+
+	v := f * float64(scale)
+	if v > math.MaxInt64 {
+		return 0, errors.New("overflow")
+	}
+
+	return time.Duration(v), nil
+}
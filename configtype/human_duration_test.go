@@ -0,0 +1,68 @@
+package configtype
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHumanDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "day", input: "1d", expected: 24 * time.Hour},
+		{name: "weeks", input: "2w", expected: 14 * 24 * time.Hour},
+		{name: "day and hours", input: "1d12h", expected: 36 * time.Hour},
+		{name: "week day minutes", input: "1w2d30m", expected: 9*24*time.Hour + 30*time.Minute},
+		{name: "fractional day", input: "1.5d", expected: 36 * time.Hour},
+		{name: "go units", input: "1h30m15s", expected: 90*time.Minute + 15*time.Second},
+		{name: "sub second", input: "250ms", expected: 250 * time.Millisecond},
+		{name: "negative", input: "-1d", expected: -24 * time.Hour},
+		{name: "zero", input: "0", expected: 0},
+		{name: "missing unit", input: "5", wantErr: true},
+		{name: "unknown unit", input: "1y", wantErr: true},
+		{name: "missing number", input: "d", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "overflow", input: "100000w", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseHumanDuration(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestHumanDurationFromEnv(t *testing.T) {
+	type Config struct {
+		RetainFor HumanDuration
+		Grace     *HumanDuration
+	}
+
+	t.Setenv("RETAIN_FOR", "2w")
+	t.Setenv("GRACE", "1d12h")
+
+	var cfg Config
+	require.NoError(t, goconfig.Load(&cfg))
+
+	assert.Equal(t, 2*Week, cfg.RetainFor.Duration())
+	assert.Equal(t, "36h0m0s", cfg.Grace.String())
+
+	text, err := cfg.RetainFor.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "336h0m0s", string(text))
+}
@@ -1,14 +1,23 @@
 package configtype
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding"
 	"encoding/json"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-var _ encoding.TextUnmarshaler = (*JSONFile[any])(nil)
+var (
+	_ encoding.TextUnmarshaler = (*JSONFile[any])(nil)
+	_ encoding.TextMarshaler   = JSONFile[any]{}
+	_ json.Marshaler           = JSONFile[any]{}
+)
 
 // JSONFile represents a configuration file in JSON format.
 // It implements encoding.TextUnmarshaler to allow loading from environment variables.
@@ -52,6 +61,36 @@ type JSONFile[T any] struct {
 	FilePath string
 	// Data contains the parsed configuration data
 	Data T
+	// UseNumber makes the decoder unmarshal numbers into interface values
+	// as json.Number instead of float64, preserving large integers.
+	// It must be set before the file is loaded.
+	UseNumber bool
+	// DisallowUnknownFields makes the decoder return an error when the file
+	// contains keys that do not match any field of Data.
+	// It must be set before the file is loaded.
+	DisallowUnknownFields bool
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It allows tests and virtual filesystems to supply content without touching disk.
+	// It must be set before the file is loaded.
+	ReadFile func(name string) ([]byte, error)
+	// DecodeHook, when set, is called for every value while the decoded file is
+	// converted into Data, e.g. StringToDurationHook to read "5s" into a
+	// time.Duration, also as the value of a map. See File. UseNumber does not
+	// apply with a hook: numbers are converted to the type of their field.
+	// It must be set before the file is loaded.
+	DecodeHook DecodeHookFunc
+	// MarshalData makes MarshalText and MarshalJSON encode Data instead of FilePath,
+	// e.g. to print the effective configuration rather than where it came from.
+	MarshalData bool
+	// ErrorSnippet adds the lines of the expanded content around a parse error
+	// to the error message. The position is reported regardless; the snippet is
+	// opt-in since expanded content may contain secrets.
+	ErrorSnippet bool
+
+	// sum is the checksum of the last loaded content
+	sum [sha256.Size]byte
+	// onReload contains the callbacks registered with OnReload
+	onReload []func(err error)
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
@@ -70,20 +109,78 @@ func (f *JSONFile[T]) UnmarshalText(data []byte) error {
 // parseJSONFile reads and parses the JSON configuration file.
 // It expands environment variables in the file content before parsing.
 func (f *JSONFile[T]) parseJSONFile() error {
-	jsonData, err := os.ReadFile(f.FilePath)
+	jsonStr, err := f.readJSONFile()
+	if err != nil {
+		return err
+	}
+
+	return loadDecoded(&f.Data, &f.sum, jsonStr, f.decodeJSON)
+}
+
+// readJSONFile reads the JSON configuration file and expands environment variables
+// in the file path and file content.
+func (f *JSONFile[T]) readJSONFile() (string, error) {
+	expandedPath := expandEnv(f.FilePath)
+
+	jsonData, err := readFile(f.ReadFile, expandedPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot load json file: %s", expandedPath)
+	}
+
+	return expandEnv(string(jsonData)), nil
+}
+
+// decodeJSON decodes the expanded JSON content into data.
+func (f *JSONFile[T]) decodeJSON(jsonStr string, data *T) error {
+	if f.DecodeHook != nil {
+		return f.decodeJSONWithHook(jsonStr, data)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(jsonStr))
+	if f.UseNumber {
+		dec.UseNumber()
+	}
+
+	if f.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	err := dec.Decode(data)
+	if err == nil {
+		err = expectJSONEOF(dec)
+	}
+
 	if err != nil {
-		return errors.Wrapf(err, "cannot load json file: %s", f.FilePath)
+		return errors.Wrapf(newParseError(err, jsonStr, f.ErrorSnippet), "failed to unmarshal json config: %s", f.FilePath)
 	}
 
-	jsonStr := os.ExpandEnv(string(jsonData))
+	return nil
+}
 
-	if err := json.Unmarshal([]byte(jsonStr), &f.Data); err != nil {
-		return errors.Wrapf(err, "failed to unmarshal json config: %s, data: %s", f.FilePath, jsonStr)
+// expectJSONEOF fails when content follows the top-level value read by dec,
+// which json.Unmarshal rejects too.
+func expectJSONEOF(dec *json.Decoder) error {
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return errors.Errorf("invalid content after top-level value at offset %d", dec.InputOffset())
 	}
 
 	return nil
 }
 
+// decodeJSONWithHook decodes the expanded JSON content into data through the decode hook.
+func (f *JSONFile[T]) decodeJSONWithHook(jsonStr string, data *T) error {
+	unknown, err := decodeWithHook(jsonStr, fileFormats[".json"], f.DecodeHook, data)
+	if err != nil {
+		return errors.Wrapf(newParseError(err, jsonStr, f.ErrorSnippet), "failed to unmarshal json config: %s", f.FilePath)
+	}
+
+	if !f.DisallowUnknownFields {
+		return nil
+	}
+
+	return errors.Wrapf(unknown, "json config: %s", f.FilePath)
+}
+
 // Reload reloads the JSON configuration file.
 // It is useful when the configuration file has been modified and needs to be reloaded.
 // If no file path is set, it returns nil without doing anything.
@@ -94,3 +191,87 @@ func (f *JSONFile[T]) Reload() error {
 
 	return f.parseJSONFile()
 }
+
+// Get returns the parsed configuration data.
+func (f *JSONFile[T]) Get() T {
+	return f.Data
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns FilePath, or Data encoded as JSON when MarshalData is set.
+func (f JSONFile[T]) MarshalText() ([]byte, error) {
+	return marshalText(f.FilePath, f.Data, f.MarshalData, fileFormats[".json"])
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It returns FilePath as a JSON string, or Data when MarshalData is set.
+func (f JSONFile[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(f.FilePath, f.Data, f.MarshalData)
+}
+
+// ReloadIfChanged reloads the JSON configuration file only when its content
+// (after environment variable expansion) changed since the last load.
+// It reports whether the content changed. When the content fails to decode, the
+// previous Data is kept and the content is loaded again by the next call.
+func (f *JSONFile[T]) ReloadIfChanged() (bool, error) {
+	if f.FilePath == "" {
+		return false, nil
+	}
+
+	jsonStr, err := f.readJSONFile()
+	if err != nil {
+		return false, err
+	}
+
+	sum := checksum(jsonStr)
+	if sum == f.sum {
+		return false, nil
+	}
+
+	return true, loadDecoded(&f.Data, &f.sum, jsonStr, f.decodeJSON)
+}
+
+// StartPolling checks the file every interval until ctx is done and calls
+// ReloadIfChanged once a change has settled for the debounce duration (see
+// WithReloadDebounce), so a burst of writes results in a single reload.
+// onChange is called with a nil error after the content changed and was reloaded,
+// and with the error when reloading fails. Data is updated from the polling goroutine,
+// so readers must synchronize with onChange.
+// The returned channel is closed once polling has stopped.
+func (f *JSONFile[T]) StartPolling(
+	ctx context.Context,
+	interval time.Duration,
+	onChange func(err error),
+	opts ...PollOption,
+) <-chan struct{} {
+	return poll(ctx, f, onChange, newPollConfig(interval, opts))
+}
+
+// peek reads the file and reports the checksum of its content and whether it
+// differs from the loaded content, without decoding it.
+func (f *JSONFile[T]) peek() ([sha256.Size]byte, bool, error) {
+	content, err := f.readJSONFile()
+	if err != nil {
+		return [sha256.Size]byte{}, false, err
+	}
+
+	sum := checksum(content)
+
+	return sum, sum != f.sum, nil
+}
+
+// OnReload registers fn to be called by ReloadOnSignal after a reload changed
+// the content (with a nil error) or failed (with the error).
+// Callbacks must be registered before ReloadOnSignal is called.
+func (f *JSONFile[T]) OnReload(fn func(err error)) {
+	f.onReload = append(f.onReload, fn)
+}
+
+// ReloadOnSignal calls ReloadIfChanged every time one of sig is received until ctx is done.
+// It listens for SIGHUP when no signal is given and stops handling the signals once
+// ctx is done, restoring their default behavior unless something else handles them.
+// The OnReload callbacks are invoked from the signal goroutine, so readers of Data
+// must synchronize with them. The returned channel is closed once it has stopped.
+func (f *JSONFile[T]) ReloadOnSignal(ctx context.Context, sig ...os.Signal) <-chan struct{} {
+	return reloadOnSignal(ctx, sig, f.ReloadIfChanged, f.onReload)
+}
@@ -1,6 +1,7 @@
 package configtype
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -150,6 +151,30 @@ func TestJSONFileImplementation(t *testing.T) {
 			t.Error("Expected error for invalid JSON, got nil")
 		}
 
+		// Test trailing content after the JSON value
+		for _, content := range []string{`{"name": "test"} garbage`, `{"name": "test"} {}`, `{"name": "test"}}`} {
+			filePath = filepath.Join(tmpDir, "trailing_config.json")
+			if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			config = &JSONFile[TestConfig]{FilePath: filePath}
+			if err := config.parseJSONFile(); err == nil {
+				t.Errorf("Expected error for trailing content in %q, got nil", content)
+			}
+		}
+
+		// Test trailing whitespace is allowed
+		filePath = filepath.Join(tmpDir, "trailing_space_config.json")
+		if err := os.WriteFile(filePath, []byte("{\"name\": \"test\"}\n\t \n"), 0o644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		config = &JSONFile[TestConfig]{FilePath: filePath}
+		if err := config.parseJSONFile(); err != nil {
+			t.Errorf("Expected trailing whitespace to be allowed, got %v", err)
+		}
+
 		// Test empty file path in Reload
 		emptyConfig := &JSONFile[TestConfig]{}
 		if err := emptyConfig.Reload(); err != nil {
@@ -157,3 +182,47 @@ func TestJSONFileImplementation(t *testing.T) {
 		}
 	})
 }
+
+func TestJSONFileDecodeOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	t.Run("use number keeps large integers", func(t *testing.T) {
+		filePath := filepath.Join(tmpDir, "large.json")
+		if err := os.WriteFile(filePath, []byte(`{"id": 9007199254740993}`), 0o644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		config := &JSONFile[map[string]any]{FilePath: filePath}
+		if err := config.parseJSONFile(); err != nil {
+			t.Fatalf("Failed to parse JSON file: %v", err)
+		}
+		if got := config.Data["id"]; got == json.Number("9007199254740993") {
+			t.Errorf("Expected float64 without UseNumber, got %v", got)
+		}
+
+		config = &JSONFile[map[string]any]{FilePath: filePath, UseNumber: true}
+		if err := config.parseJSONFile(); err != nil {
+			t.Fatalf("Failed to parse JSON file: %v", err)
+		}
+		if got := config.Data["id"]; got != json.Number("9007199254740993") {
+			t.Errorf("Expected json.Number 9007199254740993, got %v (%T)", got, got)
+		}
+	})
+
+	t.Run("disallow unknown fields", func(t *testing.T) {
+		filePath := filepath.Join(tmpDir, "unknown.json")
+		if err := os.WriteFile(filePath, []byte(`{"name": "test", "extra": true}`), 0o644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		config := &JSONFile[TestConfig]{FilePath: filePath}
+		if err := config.parseJSONFile(); err != nil {
+			t.Errorf("Expected unknown fields to be ignored, got %v", err)
+		}
+
+		config = &JSONFile[TestConfig]{FilePath: filePath, DisallowUnknownFields: true}
+		if err := config.parseJSONFile(); err == nil {
+			t.Error("Expected error for unknown field, got nil")
+		}
+	})
+}
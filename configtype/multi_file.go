@@ -0,0 +1,121 @@
+package configtype
+
+import (
+	"encoding"
+	"os"
+	"strings"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*MultiFile[any])(nil)
+	_ ConfigFile[any]          = (*MultiFile[any])(nil)
+)
+
+// MultiFile represents an ordered list of configuration files merged into one
+// value, e.g. a base file followed by environment specific overrides.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables:
+// the text is a list of paths split on Separator. Each file can use any format
+// supported by File, chosen by its extension.
+//
+// The files are decoded one after the other into the same value, so keys of a
+// later file override those of earlier ones while keys it does not set are kept.
+// Nested objects and maps are merged key by key; lists are replaced as a whole,
+// except in XML where repeated elements are appended.
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//		// export CONFIG=/etc/app/base.yaml:/etc/app/prod.yaml
+//		App configtype.MultiFile[Settings] `env:"CONFIG"`
+//	}
+type MultiFile[T any] struct {
+	// FilePaths are the paths of the configuration files, in the order they are merged
+	FilePaths []string
+	// Separator splits the path list given to UnmarshalText and defaults to the
+	// OS path list separator (":" on Unix, ";" on Windows) when empty.
+	// It must be set before the files are loaded.
+	Separator string
+	// Data contains the merged configuration data
+	Data T
+	// DecodeHook is called for every value while each file is decoded, see File.
+	// It must be set before the files are loaded.
+	DecodeHook DecodeHookFunc
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It allows tests and virtual filesystems to supply content without touching disk.
+	// It must be set before the files are loaded.
+	ReadFile func(name string) ([]byte, error)
+	// ErrorSnippet adds the lines around a parse error to the error message, see File.
+	// It must be set before the files are loaded.
+	ErrorSnippet bool
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It splits the text into file paths and loads the configuration from them.
+// The file paths can contain environment variables that will be expanded;
+// empty entries are ignored.
+func (m *MultiFile[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	sep := m.Separator
+	if sep == "" {
+		sep = string(os.PathListSeparator)
+	}
+
+	var paths []string
+
+	for _, path := range strings.Split(string(data), sep) {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	m.FilePaths = paths
+
+	return m.parseFiles()
+}
+
+// parseFiles reads all files and merges them into a new value, which replaces
+// Data only when every file was loaded.
+func (m *MultiFile[T]) parseFiles() error {
+	var data T
+
+	for _, path := range m.FilePaths {
+		// each file is decoded on top of the data merged so far
+		f := File[T]{
+			FilePath:     path,
+			DecodeHook:   m.DecodeHook,
+			ReadFile:     m.ReadFile,
+			ErrorSnippet: m.ErrorSnippet,
+		}
+
+		content, err := f.readFile()
+		if err != nil {
+			return err
+		}
+
+		if err := f.decode(content, &data); err != nil {
+			return err
+		}
+	}
+
+	m.Data = data
+
+	return nil
+}
+
+// Reload reads and merges all configuration files again. On error the previous
+// data is kept. If no file path is set, it returns nil without doing anything.
+func (m *MultiFile[T]) Reload() error {
+	if len(m.FilePaths) == 0 {
+		return nil
+	}
+
+	return m.parseFiles()
+}
+
+// Get returns the merged configuration data.
+func (m *MultiFile[T]) Get() T {
+	return m.Data
+}
@@ -0,0 +1,113 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mergedConfig struct {
+	Name   string         `json:"name" yaml:"name"`
+	Port   int            `json:"port" yaml:"port"`
+	Tags   []string       `json:"tags" yaml:"tags"`
+	Limits map[string]int `json:"limits" yaml:"limits"`
+	DB     mergedDBConfig `json:"db" yaml:"db"`
+}
+
+type mergedDBConfig struct {
+	Host string `json:"host" yaml:"host"`
+	User string `json:"user" yaml:"user"`
+}
+
+func TestMultiFile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	override := filepath.Join(dir, "override.json")
+
+	require.NoError(t, os.WriteFile(base, []byte(`
+name: api
+port: 8080
+tags: [a, b]
+limits: {read: 10, write: 5}
+db: {host: localhost, user: app}
+`), 0o644))
+	require.NoError(t, os.WriteFile(override, []byte(`{
+		"port": 9090,
+		"tags": ["c"],
+		"limits": {"write": 1},
+		"db": {"host": "${MULTI_FILE_DB_HOST}"}
+	}`), 0o644))
+
+	t.Setenv("MULTI_FILE_DIR", dir)
+	t.Setenv("MULTI_FILE_DB_HOST", "db.internal")
+
+	expected := mergedConfig{
+		Name:   "api",
+		Port:   9090,
+		Tags:   []string{"c"},
+		Limits: map[string]int{"read": 10, "write": 1},
+		DB:     mergedDBConfig{Host: "db.internal", User: "app"},
+	}
+
+	t.Run("later files win", func(t *testing.T) {
+		var m MultiFile[mergedConfig]
+		require.NoError(t, m.UnmarshalText([]byte("${MULTI_FILE_DIR}/base.yaml"+string(os.PathListSeparator)+override)))
+
+		assert.Equal(t, []string{"${MULTI_FILE_DIR}/base.yaml", override}, m.FilePaths)
+		assert.Equal(t, expected, m.Get())
+	})
+
+	t.Run("order matters", func(t *testing.T) {
+		m := MultiFile[mergedConfig]{Separator: ","}
+		require.NoError(t, m.UnmarshalText([]byte(override+", "+base+",")))
+
+		assert.Equal(t, 8080, m.Data.Port)
+		assert.Equal(t, []string{"a", "b"}, m.Data.Tags)
+		assert.Equal(t, mergedDBConfig{Host: "localhost", User: "app"}, m.Data.DB)
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		type Config struct {
+			App MultiFile[mergedConfig] `env:"MULTI_FILE_CONFIG"`
+		}
+
+		t.Setenv("MULTI_FILE_CONFIG", base+string(os.PathListSeparator)+override)
+
+		var cfg Config
+		require.NoError(t, goconfig.Load(&cfg))
+		assert.Equal(t, expected, cfg.App.Data)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		second := filepath.Join(t.TempDir(), "second.yaml")
+		writeFileAtomic(t, second, "name: first\n")
+
+		m := MultiFile[mergedConfig]{FilePaths: []string{base, second}}
+		require.NoError(t, m.Reload())
+		assert.Equal(t, "first", m.Data.Name)
+
+		writeFileAtomic(t, second, "port: 1\n")
+		require.NoError(t, m.Reload())
+		assert.Equal(t, "api", m.Data.Name, "keys removed from a later file fall back to earlier files")
+		assert.Equal(t, 1, m.Data.Port)
+
+		writeFileAtomic(t, second, "port: [")
+		assert.ErrorContains(t, m.Reload(), "failed to parse config file")
+		assert.Equal(t, 1, m.Data.Port, "data is kept on error")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		m := MultiFile[mergedConfig]{FilePaths: []string{base, filepath.Join(dir, "missing.yaml")}}
+		assert.ErrorContains(t, m.Reload(), "cannot load config file")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var m MultiFile[mergedConfig]
+		assert.NoError(t, m.UnmarshalText(nil))
+		assert.NoError(t, m.Reload())
+	})
+}
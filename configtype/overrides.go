@@ -0,0 +1,23 @@
+package configtype
+
+import "github.com/jkaveri/goconfig"
+
+// ApplyEnvOverrides loads environment variables into data using the goconfig
+// conventions and options, typically the Data of a file that was loaded first.
+// Only fields whose variables are present in the environment are changed, so
+// the values read from the file act as defaults.
+//
+// Example usage:
+//
+//	var file configtype.YAMLFile[AppConfig]
+//	if err := file.UnmarshalText([]byte("config.yaml")); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// APP_DB_HOST=db.internal overrides db.host from config.yaml
+//	if err := configtype.ApplyEnvOverrides(&file.Data, goconfig.WithPrefix("APP")); err != nil {
+//		log.Fatal(err)
+//	}
+func ApplyEnvOverrides[T any](data *T, opts ...goconfig.Option) error {
+	return goconfig.New(opts...).Load(data)
+}
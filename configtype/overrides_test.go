@@ -0,0 +1,40 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type overrideDBConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+type overrideConfig struct {
+	Name string           `yaml:"name"`
+	DB   overrideDBConfig `yaml:"db"`
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "name: api\ndb:\n  host: localhost\n  port: 5432\n"
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0o644))
+
+	var file YAMLFile[overrideConfig]
+	require.NoError(t, file.UnmarshalText([]byte(filePath)))
+
+	t.Setenv("OVR_DB_HOST", "db.internal")
+
+	err := ApplyEnvOverrides(&file.Data, goconfig.WithPrefix("OVR"))
+
+	require.NoError(t, err)
+	assert.Equal(t, overrideConfig{
+		Name: "api",
+		DB:   overrideDBConfig{Host: "db.internal", Port: 5432},
+	}, file.Data)
+}
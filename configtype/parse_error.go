@@ -0,0 +1,163 @@
+package configtype
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// snippetLines is the number of lines shown before and after the line of a
+// parse error when the snippet is enabled.
+const snippetLines = 2
+
+// yamlLine matches the line reported in yaml.v3 error messages.
+var yamlLine = regexp.MustCompile(`\bline (\d+)\b`)
+
+// ParseError is returned, wrapped, when the content of a configuration file
+// cannot be decoded. It carries the position reported by the decoder and,
+// when the ErrorSnippet field of the file type is set, the lines around it.
+// Use errors.As to retrieve it.
+type ParseError struct {
+	// Line is the 1-based line of the error, or 0 when the decoder does not report it
+	Line int
+	// Column is the 1-based column of the error, or 0 when the decoder does not report it
+	Column int
+	// Snippet contains the numbered lines of the expanded content around Line,
+	// or is empty when disabled. It may include expanded secrets.
+	Snippet string
+	// Err is the error returned by the decoder
+	Err error
+}
+
+// Error returns the decoder error, prefixed with the position when the
+// decoder message does not mention it, followed by the snippet if any.
+func (e *ParseError) Error() string {
+	msg := e.Err.Error()
+
+	var syntaxErr *json.SyntaxError
+
+	var typeErr *json.UnmarshalTypeError
+	if e.Line > 0 && (errors.As(e.Err, &syntaxErr) || errors.As(e.Err, &typeErr)) {
+		msg = fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, msg)
+	}
+
+	if e.Snippet == "" {
+		return msg
+	}
+
+	return msg + "\n" + e.Snippet
+}
+
+// Unwrap returns the decoder error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError returns err as a *ParseError holding the position of the error
+// in content, with the surrounding lines when snippet is set.
+func newParseError(err error, content string, snippet bool) error {
+	if err == nil {
+		return nil
+	}
+
+	line, col := errorPosition(err, content)
+
+	pe := &ParseError{Line: line, Column: col, Err: err}
+	if snippet && line > 0 {
+		pe.Snippet = contentSnippet(content, line, col)
+	}
+
+	return pe
+}
+
+// errorPosition returns the 1-based line and column of a decoder error, or zeros
+// when the decoder does not report them. YAML and XML only report the line.
+func errorPosition(err error, content string) (line, col int) {
+	var (
+		jsonSyntaxErr *json.SyntaxError
+		jsonTypeErr   *json.UnmarshalTypeError
+		tomlErr       toml.ParseError
+		xmlErr        *xml.SyntaxError
+		yamlErr       *yaml.TypeError
+	)
+
+	switch {
+	case errors.As(err, &jsonSyntaxErr):
+		return offsetPosition(content, jsonSyntaxErr.Offset)
+	case errors.As(err, &jsonTypeErr):
+		return offsetPosition(content, jsonTypeErr.Offset)
+	case errors.As(err, &tomlErr):
+		return tomlErr.Position.Line, tomlErr.Position.Col
+	case errors.As(err, &xmlErr):
+		return xmlErr.Line, 0
+	case errors.As(err, &yamlErr) && len(yamlErr.Errors) > 0:
+		return yamlErrorLine(yamlErr.Errors[0]), 0
+	default:
+		return yamlErrorLine(err.Error()), 0
+	}
+}
+
+// offsetPosition converts the byte offset reported by encoding/json, which
+// points just past the offending byte, into a line and column.
+func offsetPosition(content string, offset int64) (line, col int) {
+	if offset <= 0 || offset > int64(len(content)) {
+		return 0, 0
+	}
+
+	before := content[:offset]
+
+	return strings.Count(before, "\n") + 1, len(before) - strings.LastIndex(before, "\n") - 1
+}
+
+// yamlErrorLine returns the line mentioned in a yaml.v3 error message, or 0.
+func yamlErrorLine(msg string) int {
+	if !strings.HasPrefix(msg, "yaml: ") && !strings.HasPrefix(msg, "line ") {
+		return 0
+	}
+
+	m := yamlLine.FindStringSubmatch(msg)
+	if m == nil {
+		return 0
+	}
+
+	line, _ := strconv.Atoi(m[1])
+
+	return line
+}
+
+// contentSnippet returns the numbered lines of content around line, marking
+// the line with ">" and the column, when known, with a caret below it.
+func contentSnippet(content string, line, col int) string {
+	lines := strings.Split(content, "\n")
+	if line > len(lines) {
+		return ""
+	}
+
+	first := max(line-snippetLines, 1)
+	last := min(line+snippetLines, len(lines))
+	width := len(strconv.Itoa(last))
+
+	var b strings.Builder
+
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+
+		_, _ = fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, lines[n-1])
+
+		if n == line && col > 0 {
+			_, _ = fmt.Fprintf(&b, "  %*s | %s^\n", width, "", strings.Repeat(" ", col-1))
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
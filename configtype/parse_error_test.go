@@ -0,0 +1,144 @@
+package configtype
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		line    int
+		column  int
+		msg     string
+	}{
+		{
+			name:    "yaml",
+			path:    "config.yaml",
+			content: "host: localhost\nport: 80\n  bad: indent\nname: app\n",
+			line:    3,
+			msg:     "yaml: line 3",
+		},
+		{
+			name:    "yaml type",
+			path:    "config.yml",
+			content: "host: localhost\nport: eighty\n",
+			line:    2,
+			msg:     "line 2: cannot unmarshal",
+		},
+		{
+			name:    "toml",
+			path:    "config.toml",
+			content: "host = \"localhost\"\nport = = 80\n",
+			line:    2,
+			column:  8,
+			msg:     "toml: line 2",
+		},
+		{
+			name:    "json",
+			path:    "config.json",
+			content: "{\n  \"host\": \"localhost\",\n  \"port\": 80,\n}\n",
+			line:    4,
+			column:  1,
+			msg:     "line 4, column 1: invalid character '}'",
+		},
+		{
+			name:    "json type",
+			path:    "config.json",
+			content: "{\n  \"port\": \"eighty\"\n}\n",
+			line:    2,
+			column:  18,
+			msg:     "line 2, column 18: json: cannot unmarshal string",
+		},
+	}
+
+	type config struct {
+		Host string `json:"host" yaml:"host" toml:"host"`
+		Port int    `json:"port" yaml:"port" toml:"port"`
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := File[config]{ReadFile: func(string) ([]byte, error) { return []byte(tt.content), nil }}
+
+			err := f.UnmarshalText([]byte(tt.path))
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "failed to parse config file: "+tt.path)
+			assert.ErrorContains(t, err, tt.msg)
+			assert.NotContains(t, err.Error(), " | ", "no snippet unless enabled")
+
+			var pe *ParseError
+			require.True(t, errors.As(err, &pe))
+			assert.Equal(t, tt.line, pe.Line)
+			assert.Equal(t, tt.column, pe.Column)
+			assert.Empty(t, pe.Snippet)
+		})
+	}
+}
+
+func TestParseErrorSnippet(t *testing.T) {
+	content := "a: 1\nb: 2\nc: 3\n  d: 4\ne: 5\nf: 6\ng: 7\n"
+	read := func(string) ([]byte, error) { return []byte(content), nil }
+
+	t.Run("yaml", func(t *testing.T) {
+		f := YAMLFile[map[string]int]{ReadFile: read, ErrorSnippet: true}
+
+		err := f.UnmarshalText([]byte("config.yaml"))
+		require.Error(t, err)
+
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, "  2 | b: 2\n  3 | c: 3\n> 4 |   d: 4\n  5 | e: 5\n  6 | f: 6", pe.Snippet)
+		assert.Contains(t, err.Error(), pe.Snippet)
+	})
+
+	t.Run("toml caret", func(t *testing.T) {
+		f := TOMLFile[map[string]int]{
+			ReadFile:     func(string) ([]byte, error) { return []byte("a = 1\nb = = 2\n"), nil },
+			ErrorSnippet: true,
+		}
+
+		err := f.UnmarshalText([]byte("config.toml"))
+
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, "  1 | a = 1\n> 2 | b = = 2\n    |     ^\n  3 | ", pe.Snippet)
+	})
+
+	t.Run("json content only in snippet", func(t *testing.T) {
+		t.Setenv("PARSE_ERROR_TOKEN", "s3cret")
+
+		content := "{\n  \"token\": \"${PARSE_ERROR_TOKEN}\",\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3,\n  \"d\": x\n}\n"
+		read := func(string) ([]byte, error) { return []byte(content), nil }
+
+		f := JSONFile[map[string]any]{ReadFile: read}
+		err := f.UnmarshalText([]byte("config.json"))
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "s3cret", "expanded content is not included")
+
+		f = JSONFile[map[string]any]{ReadFile: read, ErrorSnippet: true}
+		err = f.UnmarshalText([]byte("config.json"))
+
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, 6, pe.Line)
+		assert.Contains(t, err.Error(), pe.Snippet)
+		assert.NotContains(t, err.Error(), "s3cret", "only the lines around the error are included")
+	})
+
+	t.Run("multi file", func(t *testing.T) {
+		m := MultiFile[map[string]int]{ReadFile: read, ErrorSnippet: true}
+
+		err := m.UnmarshalText([]byte("config.yaml"))
+
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, 4, pe.Line)
+		assert.NotEmpty(t, pe.Snippet)
+	})
+}
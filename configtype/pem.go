@@ -0,0 +1,214 @@
+package configtype
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding"
+	"encoding/pem"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*PEMCertificate)(nil)
+	_ encoding.TextUnmarshaler = (*PEMPrivateKey)(nil)
+)
+
+// pemPrefix marks inline PEM content; any other value is treated as a file path.
+var pemPrefix = []byte("-----BEGIN")
+
+// PEMCertificate represents one or more PEM encoded x509 certificates.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// The value is either inline PEM content or a path to a PEM file; the path can
+// contain environment variables that will be expanded.
+//
+// Example usage:
+//
+//	type ServerConfig struct {
+//		Cert configtype.PEMCertificate `env:"TLS_CERT"`
+//		Key  configtype.PEMPrivateKey  `env:"TLS_KEY"`
+//	}
+//
+//	// export TLS_CERT=/etc/ssl/server.crt
+//	// export TLS_KEY="$(cat /etc/ssl/server.key)"
+//
+//	var config ServerConfig
+//	if err := goconfig.Load(&config); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	cert, err := configtype.X509KeyPair(config.Cert, config.Key)
+type PEMCertificate struct {
+	// Certificates contains the parsed certificates in the order they appear,
+	// usually the leaf certificate followed by its intermediates.
+	Certificates []*x509.Certificate
+	// PEM contains the raw PEM content
+	PEM []byte
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It reads the PEM content inline or from a file and parses all CERTIFICATE blocks.
+func (c *PEMCertificate) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	content, err := readPEM(data)
+	if err != nil {
+		return err
+	}
+
+	var certs []*x509.Certificate
+
+	rest := content
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse certificate")
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return errors.New("no CERTIFICATE block found in PEM data")
+	}
+
+	c.Certificates = certs
+	c.PEM = content
+
+	return nil
+}
+
+// Leaf returns the first certificate, or nil when none is loaded.
+func (c PEMCertificate) Leaf() *x509.Certificate {
+	if len(c.Certificates) == 0 {
+		return nil
+	}
+
+	return c.Certificates[0]
+}
+
+// CertPool returns a new pool containing all loaded certificates.
+// It is typically used for RootCAs or ClientCAs of a tls.Config.
+func (c PEMCertificate) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, cert := range c.Certificates {
+		pool.AddCert(cert)
+	}
+
+	return pool
+}
+
+// PEMPrivateKey represents a PEM encoded private key.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// Like PEMCertificate, the value is either inline PEM content or a path to a PEM file.
+// PKCS #8, PKCS #1 (RSA) and SEC 1 (EC) keys are supported.
+type PEMPrivateKey struct {
+	// Key contains the parsed key, e.g. *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey
+	Key crypto.PrivateKey
+	// PEM contains the raw PEM content
+	PEM []byte
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It reads the PEM content inline or from a file and parses the first private key block.
+func (k *PEMPrivateKey) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	content, err := readPEM(data)
+	if err != nil {
+		return err
+	}
+
+	rest := content
+	for {
+		var block *pem.Block
+
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return errors.New("no private key block found in PEM data")
+		}
+
+		key, ok, err := parsePrivateKey(block)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			k.Key = key
+			k.PEM = content
+
+			return nil
+		}
+	}
+}
+
+// X509KeyPair builds a tls.Certificate from a loaded certificate chain and private key.
+func X509KeyPair(cert PEMCertificate, key PEMPrivateKey) (tls.Certificate, error) {
+	pair, err := tls.X509KeyPair(cert.PEM, key.PEM)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "failed to build key pair")
+	}
+
+	return pair, nil
+}
+
+// parsePrivateKey parses a private key block. It reports false for blocks
+// that do not hold a private key.
+func parsePrivateKey(block *pem.Block) (crypto.PrivateKey, bool, error) {
+	var (
+		key any
+		err error
+	)
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to parse %s", block.Type)
+	}
+
+	return key, true, nil
+}
+
+// readPEM returns data when it is inline PEM content and otherwise reads the
+// file it points to, expanding environment variables in the path.
+func readPEM(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), pemPrefix) {
+		return data, nil
+	}
+
+	path := expandEnv(string(data))
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load pem file: %s", path)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,87 @@
+package configtype
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultProfilesKey is the top-level key holding the profiles of a File when
+// ProfilesKey is empty.
+const DefaultProfilesKey = "profiles"
+
+// activeProfile returns the profile named by the ProfileEnv variable, or Profile.
+func (f *File[T]) activeProfile() string {
+	if f.ProfileEnv != "" {
+		if profile := os.Getenv(f.ProfileEnv); profile != "" {
+			return profile
+		}
+	}
+
+	return f.Profile
+}
+
+// profilesKey returns the top-level key holding the profiles.
+func (f *File[T]) profilesKey() string {
+	if f.ProfilesKey == "" {
+		return DefaultProfilesKey
+	}
+
+	return f.ProfilesKey
+}
+
+// applyProfile returns the content re-encoded in its format as the base
+// document, without the profiles key, merged with the subtree of profile.
+func applyProfile(content string, format fileFormat, key, profile string) (string, error) {
+	if format.tag == "" {
+		return "", errors.New("profiles are not supported for this format")
+	}
+
+	var doc map[string]any
+	if err := format.decode(content, &doc); err != nil {
+		return "", err
+	}
+
+	profiles, ok := doc[key].(map[string]any)
+	if !ok {
+		return "", errors.Errorf("no %q table of profiles found", key)
+	}
+
+	overlay, ok := profiles[profile]
+	if !ok {
+		return "", errors.Errorf("profile %q is not defined under %q", profile, key)
+	}
+
+	delete(doc, key)
+
+	merged, err := format.encode(mergeProfile(doc, overlay))
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot merge profile %q", profile)
+	}
+
+	return string(merged), nil
+}
+
+// mergeProfile merges overlay into base: maps are merged key by key, the
+// overlay winning, while lists and scalars of the overlay replace the base value.
+func mergeProfile(base, overlay any) any {
+	baseMap, ok := base.(map[string]any)
+	if !ok {
+		return overlay
+	}
+
+	overlayMap, ok := overlay.(map[string]any)
+	if !ok {
+		return overlay
+	}
+
+	for k, v := range overlayMap {
+		if b, exists := baseMap[k]; exists {
+			v = mergeProfile(b, v)
+		}
+
+		baseMap[k] = v
+	}
+
+	return baseMap
+}
@@ -0,0 +1,146 @@
+package configtype
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profileDB struct {
+	Host    string        `json:"host" yaml:"host" toml:"host"`
+	Port    int           `json:"port" yaml:"port" toml:"port"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+}
+
+type profileConfig struct {
+	Name    string            `json:"name" yaml:"name" toml:"name"`
+	DB      profileDB         `json:"db" yaml:"db" toml:"db"`
+	Origins []string          `json:"origins" yaml:"origins" toml:"origins"`
+	Labels  map[string]string `json:"labels" yaml:"labels" toml:"labels"`
+}
+
+func TestFileProfile(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+name: api
+db: {host: localhost, port: 5432, timeout: 5s}
+origins: [a.example, b.example]
+labels: {team: core, env: dev}
+profiles:
+  prod:
+    db: {host: db.prod}
+    origins: [prod.example]
+    labels: {env: prod}
+  empty: {}
+`,
+		"config.json": `{
+	"name": "api",
+	"db": {"host": "localhost", "port": 5432, "timeout": 5000000000},
+	"origins": ["a.example", "b.example"],
+	"labels": {"team": "core", "env": "dev"},
+	"profiles": {
+		"prod": {"db": {"host": "db.prod"}, "origins": ["prod.example"], "labels": {"env": "prod"}},
+		"empty": {}
+	}
+}`,
+		"config.toml": `
+name = "api"
+origins = ["a.example", "b.example"]
+db = {host = "localhost", port = 5432, timeout = "5s"}
+labels = {team = "core", env = "dev"}
+
+[profiles.prod]
+origins = ["prod.example"]
+db = {host = "db.prod"}
+labels = {env = "prod"}
+
+[profiles.empty]
+`,
+	}
+
+	base := profileConfig{
+		Name:    "api",
+		DB:      profileDB{Host: "localhost", Port: 5432, Timeout: 5 * time.Second},
+		Origins: []string{"a.example", "b.example"},
+		Labels:  map[string]string{"team": "core", "env": "dev"},
+	}
+
+	prod := profileConfig{
+		Name:    "api",
+		DB:      profileDB{Host: "db.prod", Port: 5432, Timeout: 5 * time.Second},
+		Origins: []string{"prod.example"},
+		Labels:  map[string]string{"team": "core", "env": "prod"},
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		return []byte(files[name]), nil
+	}
+
+	for name := range files {
+		t.Run(name, func(t *testing.T) {
+			f := File[profileConfig]{Profile: "prod", Strict: true, ReadFile: readFile}
+			require.NoError(t, f.UnmarshalText([]byte(name)))
+			assert.Equal(t, prod, f.Data, "the profile overrides the base")
+
+			f = File[profileConfig]{Profile: "empty", Strict: true, ReadFile: readFile}
+			require.NoError(t, f.UnmarshalText([]byte(name)))
+			assert.Equal(t, base, f.Data)
+		})
+	}
+
+	t.Run("profile env", func(t *testing.T) {
+		t.Setenv("PROFILE_TEST", "prod")
+
+		f := File[profileConfig]{Profile: "empty", ProfileEnv: "PROFILE_TEST", ReadFile: readFile}
+		require.NoError(t, f.UnmarshalText([]byte("config.yaml")))
+		assert.Equal(t, prod, f.Data, "the variable wins over Profile")
+
+		t.Setenv("PROFILE_TEST", "")
+
+		f = File[profileConfig]{Profile: "empty", ProfileEnv: "PROFILE_TEST", ReadFile: readFile}
+		require.NoError(t, f.UnmarshalText([]byte("config.yaml")))
+		assert.Equal(t, base, f.Data)
+	})
+
+	t.Run("custom key", func(t *testing.T) {
+		f := File[profileConfig]{Profile: "prod", ProfilesKey: "envs", ReadFile: func(string) ([]byte, error) {
+			return []byte("name: api\nenvs: {prod: {name: api-prod}}\n"), nil
+		}}
+
+		require.NoError(t, f.UnmarshalText([]byte("config.yaml")))
+		assert.Equal(t, "api-prod", f.Data.Name)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := map[string]struct {
+			path    string
+			profile string
+			content string
+		}{
+			`profile "staging" is not defined under "profiles"`: {"config.yaml", "staging", files["config.yaml"]},
+			`no "profiles" table of profiles found`:             {"config.yaml", "prod", "name: api\n"},
+			"profiles are not supported for this format":        {"config.xml", "prod", "<config></config>"},
+		}
+
+		for msg, tt := range tests {
+			f := File[profileConfig]{Profile: tt.profile, ReadFile: func(string) ([]byte, error) {
+				return []byte(tt.content), nil
+			}}
+
+			assert.ErrorContains(t, f.UnmarshalText([]byte(tt.path)), msg)
+		}
+	})
+
+	t.Run("parse error position", func(t *testing.T) {
+		f := File[profileConfig]{Profile: "prod", ErrorSnippet: true, ReadFile: func(string) ([]byte, error) {
+			return []byte("# api\n\nname: api\nprofiles:\n  prod: {name: api-prod}\ndb:\n  port: abc\n"), nil
+		}}
+
+		var perr *ParseError
+		require.ErrorAs(t, f.UnmarshalText([]byte("config.yaml")), &perr)
+		assert.Equal(t, 7, perr.Line)
+		assert.Contains(t, perr.Snippet, "port: abc")
+	})
+}
@@ -0,0 +1,89 @@
+package configtype
+
+import (
+	"encoding"
+	"sync"
+
+	"github.com/jkaveri/goconfig/internal/deepcopy"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*ReadOnly[any])(nil)
+	_ ConfigFile[any]          = (*ReadOnly[any])(nil)
+)
+
+// ReadOnly holds configuration data loaded from a file (see File) behind an
+// accessor that returns deep copies, so callers cannot mutate the shared config.
+// Reloads replace the data atomically, which makes it safe to share one ReadOnly
+// between goroutines while the file is reloaded. A ReadOnly must not be copied
+// after first use.
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//		Features configtype.ReadOnly[FeatureFlags] `env:"FEATURES_FILE"`
+//	}
+//
+//	flags := cfg.Features.Get() // changing flags does not affect cfg.Features
+type ReadOnly[T any] struct {
+	mu   sync.RWMutex
+	file File[T]
+	data T
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It loads the file at the path given by the text like Load.
+func (r *ReadOnly[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return r.Load(string(data))
+}
+
+// Load reads the configuration file at path, whose format is chosen by its
+// extension. The path can contain environment variables that will be expanded.
+func (r *ReadOnly[T]) Load(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.file.FilePath = path
+
+	return r.loadFile()
+}
+
+// Reload reads the configuration file again. On error the previous data is kept.
+// If no file was loaded, it returns nil without doing anything.
+func (r *ReadOnly[T]) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file.FilePath == "" {
+		return nil
+	}
+
+	return r.loadFile()
+}
+
+// loadFile decodes the file into a fresh value and publishes it. It must be called
+// with the lock held.
+func (r *ReadOnly[T]) loadFile() error {
+	var zero T
+	r.file.Data = zero
+
+	if err := r.file.parseFile(); err != nil {
+		return err
+	}
+
+	r.data = r.file.Data
+
+	return nil
+}
+
+// Get returns a deep copy of the configuration data.
+func (r *ReadOnly[T]) Get() T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return deepcopy.Copy(r.data)
+}
@@ -0,0 +1,73 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type featureFlags struct {
+	Enabled []string          `json:"enabled"`
+	Limits  map[string]int    `json:"limits"`
+	Owner   *featureFlagOwner `json:"owner"`
+}
+
+type featureFlagOwner struct {
+	Team string `json:"team"`
+}
+
+func TestReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"enabled": ["search"],
+		"limits": {"search": 10},
+		"owner": {"team": "core"}
+	}`), 0o644))
+
+	type Config struct {
+		Flags ReadOnly[featureFlags]
+	}
+
+	t.Setenv("FLAGS", path)
+
+	var cfg Config
+	require.NoError(t, goconfig.Load(&cfg))
+
+	t.Run("returns independent copies", func(t *testing.T) {
+		flags := cfg.Flags.Get()
+		flags.Enabled[0] = "mutated"
+		flags.Limits["search"] = 0
+		flags.Owner.Team = "mutated"
+
+		again := cfg.Flags.Get()
+		assert.Equal(t, []string{"search"}, again.Enabled)
+		assert.Equal(t, map[string]int{"search": 10}, again.Limits)
+		assert.Equal(t, "core", again.Owner.Team)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		before := cfg.Flags.Get()
+
+		writeFileAtomic(t, path, `{"enabled": ["chat"]}`)
+		require.NoError(t, cfg.Flags.Reload())
+
+		assert.Equal(t, featureFlags{Enabled: []string{"chat"}}, cfg.Flags.Get())
+		assert.Equal(t, []string{"search"}, before.Enabled, "earlier copies are not affected")
+	})
+
+	t.Run("failed reload keeps data", func(t *testing.T) {
+		writeFileAtomic(t, path, `{"enabled": `)
+
+		assert.Error(t, cfg.Flags.Reload())
+		assert.Equal(t, []string{"chat"}, cfg.Flags.Get().Enabled)
+	})
+
+	t.Run("reload without file", func(t *testing.T) {
+		var r ReadOnly[featureFlags]
+		assert.NoError(t, r.Reload())
+	})
+}
@@ -0,0 +1,202 @@
+package configtype
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jkaveri/goconfig/internal/deepcopy"
+	"github.com/pkg/errors"
+)
+
+// checksum identifies the content of a loaded file so reloads can detect changes.
+func checksum(content string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(content))
+}
+
+// loadDecoded decodes content with decode into a deep copy of *data and, once
+// it succeeded, stores the copy in *data and the checksum of content in *sum.
+// A failed load leaves both untouched, so data is never half-decoded and the
+// file is loaded again by the next reload even when its content is unchanged.
+// ErrUnknownField errors are reported after the known fields were decoded, so
+// the copy is stored along with them.
+func loadDecoded[T any](data *T, sum *[sha256.Size]byte, content string, decode func(content string, data *T) error) error {
+	decoded := deepcopy.Copy(*data)
+
+	err := decode(content, &decoded)
+	if err != nil && !errors.Is(err, ErrUnknownField) {
+		return err
+	}
+
+	*data = decoded
+	*sum = checksum(content)
+
+	return err
+}
+
+// DefaultReloadDebounce is how long StartPolling waits for a changed file to
+// stay unchanged before reloading it, unless WithReloadDebounce is given.
+const DefaultReloadDebounce = 100 * time.Millisecond
+
+// PollOption configures StartPolling.
+type PollOption func(*pollConfig)
+
+// pollConfig holds the settings of StartPolling.
+type pollConfig struct {
+	interval time.Duration
+	debounce time.Duration
+}
+
+func newPollConfig(interval time.Duration, opts []PollOption) pollConfig {
+	cfg := pollConfig{interval: interval, debounce: DefaultReloadDebounce}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithReloadDebounce sets how long a changed file must stay unchanged before
+// StartPolling reloads it, so editors writing in several steps or atomic
+// renames result in a single reload of the complete file. The default is
+// DefaultReloadDebounce; zero or less reloads as soon as a change is seen.
+func WithReloadDebounce(d time.Duration) PollOption {
+	return func(c *pollConfig) {
+		c.debounce = d
+	}
+}
+
+// pollable is implemented by the file types that support StartPolling.
+type pollable interface {
+	peek() ([sha256.Size]byte, bool, error)
+	ReloadIfChanged() (bool, error)
+}
+
+// poll checks f on every tick until ctx is done. A change, or an error reading
+// the file, is reloaded once the content stayed the same for the debounce
+// duration. onChange is invoked with a nil error when the reload reports a change,
+// and with the error when it fails, in which case the file is reloaded again
+// after the next tick.
+// The returned channel is closed once polling has stopped.
+func poll(
+	ctx context.Context,
+	f pollable,
+	onChange func(err error),
+	cfg pollConfig,
+) <-chan struct{} {
+	done := make(chan struct{})
+
+	reload := func() {
+		changed, err := f.ReloadIfChanged()
+		if (changed || err != nil) && onChange != nil {
+			onChange(err)
+		}
+	}
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(cfg.interval)
+		defer ticker.Stop()
+
+		var (
+			// settled fires once the pending change stayed the same for the debounce duration
+			settled <-chan time.Time
+			// last is the checksum of the pending change, zero after a read error
+			last [sha256.Size]byte
+		)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if cfg.debounce <= 0 {
+					reload()
+					continue
+				}
+
+				if settled != nil {
+					continue
+				}
+
+				if sum, changed, err := f.peek(); changed || err != nil {
+					last = sum
+					settled = time.After(cfg.debounce)
+				}
+			case <-settled:
+				sum, changed, err := f.peek()
+				if sum != last {
+					// still being written
+					last = sum
+					settled = time.After(cfg.debounce)
+
+					continue
+				}
+
+				settled = nil
+
+				if changed || err != nil {
+					reload()
+				}
+			}
+		}
+	}()
+
+	return done
+}
+
+// reloadOnSignal calls reload every time one of sigs is received until ctx is done,
+// listening for SIGHUP when no signal is given. The callbacks are invoked like
+// onChange in poll. Signal handling is stopped before the returned channel is closed.
+func reloadOnSignal(
+	ctx context.Context,
+	sigs []os.Signal,
+	reload func() (bool, error),
+	callbacks []func(err error),
+) <-chan struct{} {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer signal.Stop(ch)
+
+		watchSignals(ctx, ch, reload, callbacks)
+	}()
+
+	return done
+}
+
+// watchSignals calls reload for every value received on ch until ctx is done.
+func watchSignals(
+	ctx context.Context,
+	ch <-chan os.Signal,
+	reload func() (bool, error),
+	callbacks []func(err error),
+) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			changed, err := reload()
+			if !changed && err == nil {
+				continue
+			}
+
+			for _, fn := range callbacks {
+				fn(err)
+			}
+		}
+	}
+}
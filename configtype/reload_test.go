@@ -0,0 +1,316 @@
+package configtype
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadIfChanged(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("name: test\nversion: 1\n"), 0o644))
+
+	var config YAMLFile[TestYAMLConfig]
+	require.NoError(t, config.UnmarshalText([]byte(filePath)))
+
+	changed, err := config.ReloadIfChanged()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("name: updated\nversion: 2\n"), 0o644))
+
+	changed, err = config.ReloadIfChanged()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, TestYAMLConfig{Name: "updated", Version: 2}, config.Data)
+}
+
+func TestReloadIfChangedFailure(t *testing.T) {
+	type config struct {
+		Name    string `json:"name" yaml:"name" toml:"name" xml:"name"`
+		Version int    `json:"version" yaml:"version" toml:"version" xml:"version"`
+	}
+
+	type reloader interface {
+		UnmarshalText(data []byte) error
+		ReloadIfChanged() (bool, error)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		valid   string
+		invalid string
+		updated string
+		new     func() (reloader, func() config)
+	}{
+		{
+			name: "json", file: "config.json",
+			valid: `{"name": "a", "version": 1}`, invalid: `{"name": "half", "version": "x"}`, updated: `{"name": "b", "version": 2}`,
+			new: func() (reloader, func() config) {
+				f := &JSONFile[config]{}
+				return f, f.Get
+			},
+		},
+		{
+			name: "yaml", file: "config.yaml",
+			valid: "name: a\nversion: 1\n", invalid: "name: half\nversion: x\n", updated: "name: b\nversion: 2\n",
+			new: func() (reloader, func() config) {
+				f := &YAMLFile[config]{}
+				return f, f.Get
+			},
+		},
+		{
+			name: "toml", file: "config.toml",
+			valid: "name = \"a\"\nversion = 1\n", invalid: "name = \"half\"\nversion = \"x\"\n", updated: "name = \"b\"\nversion = 2\n",
+			new: func() (reloader, func() config) {
+				f := &TOMLFile[config]{}
+				return f, f.Get
+			},
+		},
+		{
+			name: "xml", file: "config.xml",
+			valid:   "<c><name>a</name><version>1</version></c>",
+			invalid: "<c><name>half</name><version>x</version></c>",
+			updated: "<c><name>b</name><version>2</version></c>",
+			new: func() (reloader, func() config) {
+				f := &XMLFile[config]{}
+				return f, f.Get
+			},
+		},
+		{
+			name: "file", file: "config.yaml",
+			valid: "name: a\nversion: 1\n", invalid: "name: half\nversion: x\n", updated: "name: b\nversion: 2\n",
+			new: func() (reloader, func() config) {
+				f := &File[config]{}
+				return f, f.Get
+			},
+		},
+		{
+			name: "env", file: "config.env",
+			valid:   "RELOADFAIL_NAME=a\nRELOADFAIL_VERSION=1\n",
+			invalid: "RELOADFAIL_NAME=half\nRELOADFAIL_VERSION=x\n",
+			updated: "RELOADFAIL_NAME=b\nRELOADFAIL_VERSION=2\n",
+			new: func() (reloader, func() config) {
+				f := &EnvFile[config]{Options: []goconfig.Option{goconfig.WithPrefix("RELOADFAIL")}}
+				return f, f.Get
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(filePath, []byte(tt.valid), 0o644))
+
+			f, get := tt.new()
+			require.NoError(t, f.UnmarshalText([]byte(filePath)))
+			require.Equal(t, config{Name: "a", Version: 1}, get())
+
+			require.NoError(t, os.WriteFile(filePath, []byte(tt.invalid), 0o644))
+
+			changed, err := f.ReloadIfChanged()
+			assert.True(t, changed)
+			require.Error(t, err)
+			assert.Equal(t, config{Name: "a", Version: 1}, get(), "data is not half-decoded")
+
+			changed, err = f.ReloadIfChanged()
+			assert.True(t, changed, "a failed load is retried")
+			assert.Error(t, err)
+
+			require.NoError(t, os.WriteFile(filePath, []byte(tt.updated), 0o644))
+
+			changed, err = f.ReloadIfChanged()
+			assert.True(t, changed)
+			require.NoError(t, err)
+			assert.Equal(t, config{Name: "b", Version: 2}, get())
+
+			changed, err = f.ReloadIfChanged()
+			assert.False(t, changed)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStartPolling(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"name": "test", "version": 1}`), 0o644))
+
+	var config JSONFile[TestConfig]
+	require.NoError(t, config.UnmarshalText([]byte(filePath)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan error, 10)
+	done := config.StartPolling(ctx, 10*time.Millisecond, func(err error) {
+		changes <- err
+	})
+
+	// rewriting the same content must not trigger onChange
+	writeFileAtomic(t, filePath, `{"name": "test", "version": 1}`)
+	select {
+	case err := <-changes:
+		t.Fatalf("unexpected change notification: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	writeFileAtomic(t, filePath, `{"name": "polled", "version": 2}`)
+	select {
+	case err := <-changes:
+		require.NoError(t, err)
+		assert.Equal(t, TestConfig{Name: "polled", Version: 2}, config.Data)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for change notification")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("polling did not stop after cancel")
+	}
+}
+
+func TestReloadDebounce(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"name": "test", "version": 1}`), 0o644))
+
+	var config JSONFile[TestConfig]
+	require.NoError(t, config.UnmarshalText([]byte(filePath)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan error, 10)
+	done := config.StartPolling(ctx, 5*time.Millisecond, func(err error) {
+		changes <- err
+	}, WithReloadDebounce(200*time.Millisecond))
+
+	// a burst of writes, including a partially written file
+	for i := range 5 {
+		writeFileAtomic(t, filePath, fmt.Sprintf(`{"name": "write %d", "version": `, i))
+		time.Sleep(10 * time.Millisecond)
+		writeFileAtomic(t, filePath, fmt.Sprintf(`{"name": "write %d", "version": %d}`, i, i))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err := <-changes:
+		require.NoError(t, err)
+		assert.Equal(t, TestConfig{Name: "write 4", Version: 4}, config.Data)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for change notification")
+	}
+
+	select {
+	case err := <-changes:
+		t.Fatalf("unexpected second reload: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	cancel()
+	<-done
+}
+
+func TestWatchSignals(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := []struct {
+		changed bool
+		err     error
+	}{
+		{changed: false},
+		{changed: true},
+		{err: errors.New("broken file")},
+	}
+
+	var calls []error
+
+	sigs := make(chan os.Signal, len(results))
+	reload := func() (bool, error) {
+		r := results[0]
+		results = results[1:]
+
+		if len(results) == 0 {
+			defer cancel()
+		}
+
+		return r.changed, r.err
+	}
+
+	for range results {
+		sigs <- syscall.SIGHUP
+	}
+
+	watchSignals(ctx, sigs, reload, []func(err error){
+		func(err error) { calls = append(calls, err) },
+	})
+
+	// the unchanged reload is not reported
+	require.Len(t, calls, 2)
+	assert.NoError(t, calls[0])
+	assert.EqualError(t, calls[1], "broken file")
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to the current process on windows")
+	}
+
+	filePath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"name": "test", "version": 1}`), 0o644))
+
+	var config JSONFile[TestConfig]
+	require.NoError(t, config.UnmarshalText([]byte(filePath)))
+
+	changes := make(chan error, 1)
+	config.OnReload(func(err error) {
+		changes <- err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := config.ReloadOnSignal(ctx)
+
+	writeFileAtomic(t, filePath, `{"name": "signaled", "version": 2}`)
+
+	proc, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, proc.Signal(syscall.SIGHUP))
+
+	select {
+	case err := <-changes:
+		require.NoError(t, err)
+		assert.Equal(t, TestConfig{Name: "signaled", Version: 2}, config.Data)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("signal handling did not stop after cancel")
+	}
+}
+
+// writeFileAtomic replaces the file content with a rename so pollers never observe a partial write.
+func writeFileAtomic(t *testing.T, path, content string) {
+	t.Helper()
+
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0o644))
+	require.NoError(t, os.Rename(tmp, path))
+}
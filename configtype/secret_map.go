@@ -0,0 +1,87 @@
+package configtype
+
+import (
+	"encoding"
+	"encoding/json"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*SecretMap)(nil)
+	_ goconfig.KVSource        = secretMapSource(nil)
+)
+
+// SecretMap represents a JSON object of secrets delivered in a single environment
+// variable, as secret managers such as AWS Secrets Manager or GCP Secret Manager
+// usually provide them.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// String values are kept as they are; other JSON values (numbers, booleans, objects)
+// are kept as their JSON text.
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//		// export APP_SECRETS='{"DB_PASSWORD":"s3cret","API_TOKEN":"abc"}'
+//		Secrets configtype.SecretMap `env:"APP_SECRETS"`
+//	}
+//
+//	token, _ := config.Secrets.Get("API_TOKEN")
+//
+//	// route the DB_ entries into the fields of a struct
+//	var db struct{ Password string }
+//	if err := config.Secrets.Load(&db, goconfig.WithPrefix("DB")); err != nil {
+//		log.Fatal(err)
+//	}
+type SecretMap map[string]string
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It decodes the JSON object held by the environment variable.
+func (m *SecretMap) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return errors.Wrap(err, "failed to parse secret map")
+	}
+
+	secrets := make(SecretMap, len(raw))
+
+	for k, v := range raw {
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			s = string(v)
+		}
+
+		secrets[k] = s
+	}
+
+	*m = secrets
+
+	return nil
+}
+
+// Get returns the secret stored under key and reports whether it exists.
+func (m SecretMap) Get(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// Load loads the secrets into the fields of data as if they were environment
+// variables, using the goconfig conventions and options. A prefix given with
+// goconfig.WithPrefix selects the entries for data, e.g. "DB" routes DB_PASSWORD
+// to the Password field. The environment itself is not read.
+func (m SecretMap) Load(data any, opts ...goconfig.Option) error {
+	return goconfig.New(append(opts[:len(opts):len(opts)], goconfig.WithKVSource(secretMapSource(m), goconfig.KVSourceOnly))...).Load(data)
+}
+
+// secretMapSource adapts a SecretMap to goconfig.KVSource.
+type secretMapSource SecretMap
+
+func (s secretMapSource) Get(key string) (string, bool, error) {
+	v, ok := s[key]
+	return v, ok, nil
+}
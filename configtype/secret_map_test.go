@@ -0,0 +1,87 @@
+package configtype
+
+import (
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretMap(t *testing.T) {
+	t.Run("decode", func(t *testing.T) {
+		var m SecretMap
+		require.NoError(t, m.UnmarshalText([]byte(`{"DB_PASSWORD":"s3cret","DB_PORT":5432,"TLS":true}`)))
+
+		assert.Equal(t, SecretMap{"DB_PASSWORD": "s3cret", "DB_PORT": "5432", "TLS": "true"}, m)
+
+		v, ok := m.Get("DB_PASSWORD")
+		assert.True(t, ok)
+		assert.Equal(t, "s3cret", v)
+
+		_, ok = m.Get("MISSING")
+		assert.False(t, ok)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var m SecretMap
+		assert.NoError(t, m.UnmarshalText(nil))
+		assert.Nil(t, m)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		var m SecretMap
+		assert.ErrorContains(t, m.UnmarshalText([]byte(`["a"]`)), "failed to parse secret map")
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		type Config struct {
+			Secrets SecretMap `env:"SECRET_MAP_TEST"`
+		}
+
+		t.Setenv("SECRET_MAP_TEST", `{"API_TOKEN":"abc"}`)
+
+		var cfg Config
+		require.NoError(t, goconfig.Load(&cfg))
+		assert.Equal(t, SecretMap{"API_TOKEN": "abc"}, cfg.Secrets)
+	})
+
+	t.Run("route into fields", func(t *testing.T) {
+		type DB struct {
+			User     string
+			Password string
+			Port     int
+		}
+
+		m := SecretMap{"DB_USER": "app", "DB_PASSWORD": "s3cret", "DB_PORT": "5432", "API_TOKEN": "abc"}
+
+		// the environment is not read
+		t.Setenv("DB_USER", "from-env")
+
+		var db DB
+		require.NoError(t, m.Load(&db, goconfig.WithPrefix("DB")))
+		assert.Equal(t, DB{User: "app", Password: "s3cret", Port: 5432}, db)
+	})
+
+	t.Run("options are not modified", func(t *testing.T) {
+		type DB struct {
+			User string
+		}
+
+		m := SecretMap{"DB_USER": "app"}
+		other := SecretMap{"DB_USER": "other"}
+
+		opts := make([]goconfig.Option, 1, 2)
+		opts[0] = goconfig.WithPrefix("DB")
+
+		var db, db2 DB
+		require.NoError(t, m.Load(&db, opts...))
+		require.NoError(t, other.Load(&db2, opts...))
+
+		// a spare capacity slot written by Load would be visible here
+		opts = opts[:2]
+		assert.Nil(t, opts[1])
+		assert.Equal(t, "app", db.User)
+		assert.Equal(t, "other", db2.User)
+	})
+}
@@ -0,0 +1,44 @@
+package configtype
+
+// Set is a set of comparable values for O(1) membership checks.
+// The Loader fills it, like any map[T]struct{} field, from a list separated by
+// the array separator (or the sep tag); every element becomes a key and
+// duplicates collapse into one.
+//
+// Example usage:
+//
+//	type Config struct {
+//		// export ALLOWED_PORTS=80,443,8080
+//		AllowedPorts configtype.Set[int] `env:"ALLOWED_PORTS"`
+//	}
+//
+//	if cfg.AllowedPorts.Has(port) {
+//		...
+//	}
+type Set[T comparable] map[T]struct{}
+
+// NewSet returns a set holding the given values.
+func NewSet[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+
+	return s
+}
+
+// Has reports whether v is in the set.
+func (s Set[T]) Has(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// Values returns the values of the set in unspecified order.
+func (s Set[T]) Values() []T {
+	values := make([]T, 0, len(s))
+	for v := range s {
+		values = append(values, v)
+	}
+
+	return values
+}
@@ -0,0 +1,34 @@
+package configtype
+
+import (
+	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet("a", "b", "a")
+
+	assert.Len(t, s, 2)
+	assert.True(t, s.Has("a"))
+	assert.False(t, s.Has("c"))
+	assert.ElementsMatch(t, []string{"a", "b"}, s.Values())
+}
+
+func TestSetLoad(t *testing.T) {
+	type Config struct {
+		Ports Set[int]
+		Hosts Set[string] `sep:";"`
+	}
+
+	t.Setenv("SET_TEST_PORTS", "80,443,80")
+	t.Setenv("SET_TEST_HOSTS", "a.example;b.example")
+
+	var cfg Config
+	require.NoError(t, goconfig.New(goconfig.WithPrefix("SET_TEST")).Load(&cfg))
+
+	assert.Equal(t, NewSet(80, 443), cfg.Ports)
+	assert.True(t, cfg.Hosts.Has("b.example"))
+}
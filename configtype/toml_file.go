@@ -1,14 +1,22 @@
 package configtype
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding"
+	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/pkg/errors"
 )
 
-var _ encoding.TextUnmarshaler = (*TOMLFile[any])(nil)
+var (
+	_ encoding.TextUnmarshaler = (*TOMLFile[any])(nil)
+	_ encoding.TextMarshaler   = TOMLFile[any]{}
+	_ json.Marshaler           = TOMLFile[any]{}
+)
 
 // TOMLFile represents a configuration file in TOML format.
 // It implements encoding.TextUnmarshaler to allow loading from environment variables.
@@ -52,6 +60,27 @@ type TOMLFile[T any] struct {
 	FilePath string
 	// Data contains the parsed configuration data
 	Data T
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It allows tests and virtual filesystems to supply content without touching disk.
+	// It must be set before the file is loaded.
+	ReadFile func(name string) ([]byte, error)
+	// DecodeHook, when set, is called for every value while the decoded file is
+	// converted into Data, e.g. StringToDurationHook to read "5s" into a
+	// time.Duration, also as the value of a map. See File.
+	// It must be set before the file is loaded.
+	DecodeHook DecodeHookFunc
+	// MarshalData makes MarshalText and MarshalJSON encode Data instead of FilePath,
+	// e.g. to print the effective configuration rather than where it came from.
+	MarshalData bool
+	// ErrorSnippet adds the lines of the expanded content around a parse error
+	// to the error message. The position is reported regardless; the snippet is
+	// opt-in since expanded content may contain secrets.
+	ErrorSnippet bool
+
+	// sum is the checksum of the last loaded content
+	sum [sha256.Size]byte
+	// onReload contains the callbacks registered with OnReload
+	onReload []func(err error)
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
@@ -73,21 +102,43 @@ func (f *TOMLFile[T]) parseTOMLFile() error {
 		return nil
 	}
 
+	content, err := f.readTOMLFile()
+	if err != nil {
+		return err
+	}
+
+	return loadDecoded(&f.Data, &f.sum, content, f.decodeTOML)
+}
+
+// readTOMLFile reads the TOML configuration file and expands environment variables
+// in the file path and file content.
+func (f *TOMLFile[T]) readTOMLFile() (string, error) {
 	// Expand environment variables in the file path
-	expandedPath := os.ExpandEnv(f.FilePath)
+	expandedPath := expandEnv(f.FilePath)
 
 	// Read the file
-	content, err := os.ReadFile(expandedPath)
+	content, err := readFile(f.ReadFile, expandedPath)
 	if err != nil {
-		return errors.Wrapf(err, "failed to read TOML file: %s", expandedPath)
+		return "", errors.Wrapf(err, "failed to read TOML file: %s", expandedPath)
 	}
 
 	// Expand environment variables in the content
-	expandedContent := os.ExpandEnv(string(content))
+	return expandEnv(string(content)), nil
+}
+
+// decodeTOML decodes the expanded TOML content into data.
+func (f *TOMLFile[T]) decodeTOML(content string, data *T) error {
+	var err error
 
 	// Parse TOML content
-	if _, err := toml.Decode(expandedContent, &f.Data); err != nil {
-		return errors.Wrapf(err, "failed to parse TOML file: %s", expandedPath)
+	if f.DecodeHook != nil {
+		_, err = decodeWithHook(content, fileFormats[".toml"], f.DecodeHook, data)
+	} else {
+		_, err = toml.Decode(content, data)
+	}
+
+	if err != nil {
+		return errors.Wrapf(newParseError(err, content, f.ErrorSnippet), "failed to parse TOML file: %s", expandEnv(f.FilePath))
 	}
 
 	return nil
@@ -98,3 +149,87 @@ func (f *TOMLFile[T]) parseTOMLFile() error {
 func (f *TOMLFile[T]) Reload() error {
 	return f.parseTOMLFile()
 }
+
+// Get returns the parsed configuration data.
+func (f *TOMLFile[T]) Get() T {
+	return f.Data
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns FilePath, or Data encoded as TOML when MarshalData is set.
+func (f TOMLFile[T]) MarshalText() ([]byte, error) {
+	return marshalText(f.FilePath, f.Data, f.MarshalData, fileFormats[".toml"])
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It returns FilePath as a JSON string, or Data when MarshalData is set.
+func (f TOMLFile[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(f.FilePath, f.Data, f.MarshalData)
+}
+
+// ReloadIfChanged reloads the TOML configuration file only when its content
+// (after environment variable expansion) changed since the last load.
+// It reports whether the content changed. When the content fails to decode, the
+// previous Data is kept and the content is loaded again by the next call.
+func (f *TOMLFile[T]) ReloadIfChanged() (bool, error) {
+	if f.FilePath == "" {
+		return false, nil
+	}
+
+	content, err := f.readTOMLFile()
+	if err != nil {
+		return false, err
+	}
+
+	sum := checksum(content)
+	if sum == f.sum {
+		return false, nil
+	}
+
+	return true, loadDecoded(&f.Data, &f.sum, content, f.decodeTOML)
+}
+
+// StartPolling checks the file every interval until ctx is done and calls
+// ReloadIfChanged once a change has settled for the debounce duration (see
+// WithReloadDebounce), so a burst of writes results in a single reload.
+// onChange is called with a nil error after the content changed and was reloaded,
+// and with the error when reloading fails. Data is updated from the polling goroutine,
+// so readers must synchronize with onChange.
+// The returned channel is closed once polling has stopped.
+func (f *TOMLFile[T]) StartPolling(
+	ctx context.Context,
+	interval time.Duration,
+	onChange func(err error),
+	opts ...PollOption,
+) <-chan struct{} {
+	return poll(ctx, f, onChange, newPollConfig(interval, opts))
+}
+
+// peek reads the file and reports the checksum of its content and whether it
+// differs from the loaded content, without decoding it.
+func (f *TOMLFile[T]) peek() ([sha256.Size]byte, bool, error) {
+	content, err := f.readTOMLFile()
+	if err != nil {
+		return [sha256.Size]byte{}, false, err
+	}
+
+	sum := checksum(content)
+
+	return sum, sum != f.sum, nil
+}
+
+// OnReload registers fn to be called by ReloadOnSignal after a reload changed
+// the content (with a nil error) or failed (with the error).
+// Callbacks must be registered before ReloadOnSignal is called.
+func (f *TOMLFile[T]) OnReload(fn func(err error)) {
+	f.onReload = append(f.onReload, fn)
+}
+
+// ReloadOnSignal calls ReloadIfChanged every time one of sig is received until ctx is done.
+// It listens for SIGHUP when no signal is given and stops handling the signals once
+// ctx is done, restoring their default behavior unless something else handles them.
+// The OnReload callbacks are invoked from the signal goroutine, so readers of Data
+// must synchronize with them. The returned channel is closed once it has stopped.
+func (f *TOMLFile[T]) ReloadOnSignal(ctx context.Context, sig ...os.Signal) <-chan struct{} {
+	return reloadOnSignal(ctx, sig, f.ReloadIfChanged, f.onReload)
+}
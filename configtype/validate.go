@@ -0,0 +1,50 @@
+package configtype
+
+import (
+	stderrors "errors"
+
+	"github.com/pkg/errors"
+)
+
+// Validator is implemented by configuration types that check their own values.
+// LoadAndValidate calls Validate on the decoded data.
+type Validator interface {
+	Validate() error
+}
+
+// LoadAndValidate loads the configuration file at path with File[T] in strict mode
+// and calls Validate when T or *T implements Validator. Unknown keys (ErrUnknownField)
+// and the validation error are reported together, so all problems of the file show
+// up at once; the decoded data is returned in every case but a read or syntax error.
+//
+// Example usage:
+//
+//	cfg, err := configtype.LoadAndValidate[AppConfig]("config.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func LoadAndValidate[T any](path string) (T, error) {
+	f := File[T]{FilePath: path, Strict: true}
+
+	var problems []error
+
+	if err := f.parseFile(); err != nil {
+		if !errors.Is(err, ErrUnknownField) {
+			return f.Data, err
+		}
+
+		problems = append(problems, err)
+	}
+
+	if v, ok := any(&f.Data).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			problems = append(problems, errors.Wrap(err, "validation failed"))
+		}
+	}
+
+	if len(problems) > 0 {
+		return f.Data, errors.Wrapf(stderrors.Join(problems...), "invalid config file %s", expandEnv(path))
+	}
+
+	return f.Data, nil
+}
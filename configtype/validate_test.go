@@ -0,0 +1,72 @@
+package configtype
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedConfig struct {
+	Name    string `yaml:"name"`
+	Port    int    `yaml:"port"`
+	Workers int    `yaml:"workers"`
+}
+
+func (c *validatedConfig) Validate() error {
+	var errs []error
+
+	if c.Port <= 0 || c.Port > 65535 {
+		errs = append(errs, errors.Errorf("port %d out of range", c.Port))
+	}
+
+	if c.Workers < 1 {
+		errs = append(errs, errors.New("workers must be at least 1"))
+	}
+
+	return stderrors.Join(errs...)
+}
+
+func TestLoadAndValidate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	t.Run("valid", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "valid.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("name: api\nport: 8080\nworkers: 4\n"), 0o644))
+
+		cfg, err := LoadAndValidate[validatedConfig](path)
+
+		require.NoError(t, err)
+		assert.Equal(t, validatedConfig{Name: "api", Port: 8080, Workers: 4}, cfg)
+	})
+
+	t.Run("multiple problems", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "invalid.yaml")
+		content := "name: api\nport: 70000\nworkres: 4\nlisten: :80\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		cfg, err := LoadAndValidate[validatedConfig](path)
+
+		assert.ErrorIs(t, err, ErrUnknownField)
+		assert.ErrorContains(t, err, "field workres not found")
+		assert.ErrorContains(t, err, "field listen not found")
+		assert.ErrorContains(t, err, "port 70000 out of range")
+		assert.ErrorContains(t, err, "workers must be at least 1")
+		assert.Equal(t, "api", cfg.Name)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "broken.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"name":`), 0o644))
+
+		_, err := LoadAndValidate[validatedConfig](path)
+		assert.ErrorContains(t, err, "failed to parse config file")
+
+		var perr *ParseError
+		assert.ErrorAs(t, err, &perr)
+	})
+}
@@ -0,0 +1,237 @@
+package configtype
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ encoding.TextUnmarshaler = (*XMLFile[any])(nil)
+	_ encoding.TextMarshaler   = XMLFile[any]{}
+	_ json.Marshaler           = XMLFile[any]{}
+)
+
+// XMLFile represents a configuration file in XML format.
+// It implements encoding.TextUnmarshaler to allow loading from environment variables.
+// The generic type T specifies the type of the configuration data.
+//
+// Environment variables in the content are expanded with their values XML-escaped,
+// so a value such as "a&b<c" cannot break the document.
+//
+// Example usage:
+//
+//	type DBConfig struct {
+//		Host     string `xml:"host"`
+//		Port     int    `xml:"port"`
+//		Password string `xml:"password"`
+//	}
+//
+//	type AppConfig struct {
+//		DB configtype.XMLFile[DBConfig] `env:"DB_CONFIG"`
+//	}
+//
+//	// Set environment variable to point to XML config file
+//	// export DB_CONFIG=/path/to/db_config.xml
+//	// export DB_PASSWORD='p&ss<word'
+//
+//	// The XML file at /path/to/db_config.xml can contain environment variables:
+//	// <db>
+//	//   <host>localhost</host>
+//	//   <port>5432</port>
+//	//   <password>$DB_PASSWORD</password>
+//	// </db>
+//
+//	// Load configuration
+//	var config AppConfig
+//	if err := goconfig.Load(&config); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// Access configuration
+//	fmt.Printf("Database: %s:%d\n",
+//		config.DB.Data.Host,
+//		config.DB.Data.Port)
+type XMLFile[T any] struct {
+	// FilePath is the path to the XML configuration file
+	FilePath string
+	// Data contains the parsed configuration data
+	Data T
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It allows tests and virtual filesystems to supply content without touching disk.
+	// It must be set before the file is loaded.
+	ReadFile func(name string) ([]byte, error)
+	// MarshalData makes MarshalText and MarshalJSON encode Data instead of FilePath,
+	// e.g. to print the effective configuration rather than where it came from.
+	MarshalData bool
+	// ErrorSnippet adds the lines of the expanded content around a parse error
+	// to the error message. The position is reported regardless; the snippet is
+	// opt-in since expanded content may contain secrets.
+	ErrorSnippet bool
+
+	// sum is the checksum of the last loaded content
+	sum [sha256.Size]byte
+	// onReload contains the callbacks registered with OnReload
+	onReload []func(err error)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It reads the XML file path from the provided text and loads the configuration.
+// The file path can contain environment variables that will be expanded.
+func (f *XMLFile[T]) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	f.FilePath = string(data)
+	return f.parseXMLFile()
+}
+
+// parseXMLFile reads and parses the XML configuration file.
+// It expands any environment variables in the file path and file content.
+func (f *XMLFile[T]) parseXMLFile() error {
+	if f.FilePath == "" {
+		return nil
+	}
+
+	content, err := f.readXMLFile()
+	if err != nil {
+		return err
+	}
+
+	return loadDecoded(&f.Data, &f.sum, content, f.decodeXML)
+}
+
+// readXMLFile reads the XML configuration file and expands environment variables
+// in the file path and file content. Values expanded in the content are XML-escaped.
+func (f *XMLFile[T]) readXMLFile() (string, error) {
+	// Expand environment variables in the file path
+	expandedPath := expandEnv(f.FilePath)
+
+	// Read the file
+	content, err := readFile(f.ReadFile, expandedPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read XML file: %s", expandedPath)
+	}
+
+	// Expand environment variables in the content, escaping their values
+	return os.Expand(string(content), xmlEscapedEnv), nil
+}
+
+// decodeXML decodes the expanded XML content into data.
+func (f *XMLFile[T]) decodeXML(content string, data *T) error {
+	// Parse XML content
+	if err := xml.Unmarshal([]byte(content), data); err != nil {
+		return errors.Wrapf(newParseError(err, content, f.ErrorSnippet), "failed to parse XML file: %s", expandEnv(f.FilePath))
+	}
+
+	return nil
+}
+
+// xmlEscapedEnv resolves a variable reference like expandEnv and escapes
+// the result for XML text and attributes.
+func xmlEscapedEnv(name string) string {
+	var sb strings.Builder
+
+	// EscapeText only fails when the writer fails, which strings.Builder never does
+	_ = xml.EscapeText(&sb, []byte(envWithDefault(name)))
+
+	return sb.String()
+}
+
+// Reload reloads the XML configuration file.
+// This is useful when the configuration file has been updated and you want to load the new values.
+func (f *XMLFile[T]) Reload() error {
+	return f.parseXMLFile()
+}
+
+// Get returns the parsed configuration data.
+func (f *XMLFile[T]) Get() T {
+	return f.Data
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns FilePath, or Data encoded as XML when MarshalData is set.
+func (f XMLFile[T]) MarshalText() ([]byte, error) {
+	return marshalText(f.FilePath, f.Data, f.MarshalData, fileFormats[".xml"])
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It returns FilePath as a JSON string, or Data when MarshalData is set.
+func (f XMLFile[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(f.FilePath, f.Data, f.MarshalData)
+}
+
+// ReloadIfChanged reloads the XML configuration file only when its content
+// (after environment variable expansion) changed since the last load.
+// It reports whether the content changed. When the content fails to decode, the
+// previous Data is kept and the content is loaded again by the next call.
+func (f *XMLFile[T]) ReloadIfChanged() (bool, error) {
+	if f.FilePath == "" {
+		return false, nil
+	}
+
+	content, err := f.readXMLFile()
+	if err != nil {
+		return false, err
+	}
+
+	sum := checksum(content)
+	if sum == f.sum {
+		return false, nil
+	}
+
+	return true, loadDecoded(&f.Data, &f.sum, content, f.decodeXML)
+}
+
+// StartPolling checks the file every interval until ctx is done and calls
+// ReloadIfChanged once a change has settled for the debounce duration (see
+// WithReloadDebounce), so a burst of writes results in a single reload.
+// onChange is called with a nil error after the content changed and was reloaded,
+// and with the error when reloading fails. Data is updated from the polling goroutine,
+// so readers must synchronize with onChange.
+// The returned channel is closed once polling has stopped.
+func (f *XMLFile[T]) StartPolling(
+	ctx context.Context,
+	interval time.Duration,
+	onChange func(err error),
+	opts ...PollOption,
+) <-chan struct{} {
+	return poll(ctx, f, onChange, newPollConfig(interval, opts))
+}
+
+// peek reads the file and reports the checksum of its content and whether it
+// differs from the loaded content, without decoding it.
+func (f *XMLFile[T]) peek() ([sha256.Size]byte, bool, error) {
+	content, err := f.readXMLFile()
+	if err != nil {
+		return [sha256.Size]byte{}, false, err
+	}
+
+	sum := checksum(content)
+
+	return sum, sum != f.sum, nil
+}
+
+// OnReload registers fn to be called by ReloadOnSignal after a reload changed
+// the content (with a nil error) or failed (with the error).
+// Callbacks must be registered before ReloadOnSignal is called.
+func (f *XMLFile[T]) OnReload(fn func(err error)) {
+	f.onReload = append(f.onReload, fn)
+}
+
+// ReloadOnSignal calls ReloadIfChanged every time one of sig is received until ctx is done.
+// It listens for SIGHUP when no signal is given and stops handling the signals once
+// ctx is done, restoring their default behavior unless something else handles them.
+// The OnReload callbacks are invoked from the signal goroutine, so readers of Data
+// must synchronize with them. The returned channel is closed once it has stopped.
+func (f *XMLFile[T]) ReloadOnSignal(ctx context.Context, sig ...os.Signal) <-chan struct{} {
+	return reloadOnSignal(ctx, sig, f.ReloadIfChanged, f.onReload)
+}
@@ -0,0 +1,70 @@
+package configtype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestXMLConfig struct {
+	Name     string `xml:"name"`
+	Version  int    `xml:"version"`
+	Password string `xml:"password"`
+	Label    string `xml:"label,attr"`
+}
+
+func TestXMLFileImplementation(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("valid xml file", func(t *testing.T) {
+		path := write("config.xml", `<config><name>test</name><version>1</version></config>`)
+
+		var config XMLFile[TestXMLConfig]
+		require.NoError(t, config.UnmarshalText([]byte(path)))
+		assert.Equal(t, TestXMLConfig{Name: "test", Version: 1}, config.Data)
+	})
+
+	t.Run("expanded values are escaped", func(t *testing.T) {
+		t.Setenv("TEST_XML_PASSWORD", `p&ss<word>`)
+		t.Setenv("TEST_XML_LABEL", `"quoted" & <tagged>`)
+
+		path := write("env_config.xml",
+			`<config label="$TEST_XML_LABEL"><name>test</name><password>${TEST_XML_PASSWORD}</password></config>`)
+
+		var config XMLFile[TestXMLConfig]
+		require.NoError(t, config.UnmarshalText([]byte(path)))
+		assert.Equal(t, `p&ss<word>`, config.Data.Password)
+		assert.Equal(t, `"quoted" & <tagged>`, config.Data.Label)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		path := write("reload_config.xml", `<config><name>test</name></config>`)
+
+		var config XMLFile[TestXMLConfig]
+		require.NoError(t, config.UnmarshalText([]byte(path)))
+
+		write("reload_config.xml", `<config><name>reloaded</name></config>`)
+		require.NoError(t, config.Reload())
+		assert.Equal(t, "reloaded", config.Data.Name)
+	})
+
+	t.Run("error cases", func(t *testing.T) {
+		config := XMLFile[TestXMLConfig]{FilePath: filepath.Join(tmpDir, "missing.xml")}
+		assert.Error(t, config.Reload())
+
+		path := write("invalid.xml", `<config><name>test</config>`)
+		assert.Error(t, config.UnmarshalText([]byte(path)))
+
+		var empty XMLFile[TestXMLConfig]
+		assert.NoError(t, empty.UnmarshalText(nil))
+	})
+}
@@ -1,14 +1,22 @@
 package configtype
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding"
+	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
 
-var _ encoding.TextUnmarshaler = (*YAMLFile[any])(nil)
+var (
+	_ encoding.TextUnmarshaler = (*YAMLFile[any])(nil)
+	_ encoding.TextMarshaler   = YAMLFile[any]{}
+	_ json.Marshaler           = YAMLFile[any]{}
+)
 
 // YAMLFile represents a configuration file in YAML format.
 // It implements encoding.TextUnmarshaler to allow loading from environment variables.
@@ -52,6 +60,27 @@ type YAMLFile[T any] struct {
 	FilePath string
 	// Data contains the parsed configuration data
 	Data T
+	// ReadFile reads the file content and defaults to os.ReadFile when nil.
+	// It allows tests and virtual filesystems to supply content without touching disk.
+	// It must be set before the file is loaded.
+	ReadFile func(name string) ([]byte, error)
+	// DecodeHook, when set, is called for every value while the decoded file is
+	// converted into Data, e.g. StringToDurationHook to read "5s" into a
+	// time.Duration, also as the value of a map. See File.
+	// It must be set before the file is loaded.
+	DecodeHook DecodeHookFunc
+	// MarshalData makes MarshalText and MarshalJSON encode Data instead of FilePath,
+	// e.g. to print the effective configuration rather than where it came from.
+	MarshalData bool
+	// ErrorSnippet adds the lines of the expanded content around a parse error
+	// to the error message. The position is reported regardless; the snippet is
+	// opt-in since expanded content may contain secrets.
+	ErrorSnippet bool
+
+	// sum is the checksum of the last loaded content
+	sum [sha256.Size]byte
+	// onReload contains the callbacks registered with OnReload
+	onReload []func(err error)
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
@@ -73,21 +102,43 @@ func (f *YAMLFile[T]) parseYAMLFile() error {
 		return nil
 	}
 
+	content, err := f.readYAMLFile()
+	if err != nil {
+		return err
+	}
+
+	return loadDecoded(&f.Data, &f.sum, content, f.decodeYAML)
+}
+
+// readYAMLFile reads the YAML configuration file and expands environment variables
+// in the file path and file content.
+func (f *YAMLFile[T]) readYAMLFile() (string, error) {
 	// Expand environment variables in the file path
-	expandedPath := os.ExpandEnv(f.FilePath)
+	expandedPath := expandEnv(f.FilePath)
 
 	// Read the file
-	content, err := os.ReadFile(expandedPath)
+	content, err := readFile(f.ReadFile, expandedPath)
 	if err != nil {
-		return errors.Wrapf(err, "failed to read YAML file: %s", expandedPath)
+		return "", errors.Wrapf(err, "failed to read YAML file: %s", expandedPath)
 	}
 
 	// Expand environment variables in the content
-	expandedContent := os.ExpandEnv(string(content))
+	return expandEnv(string(content)), nil
+}
+
+// decodeYAML decodes the expanded YAML content into data.
+func (f *YAMLFile[T]) decodeYAML(content string, data *T) error {
+	var err error
 
 	// Parse YAML content
-	if err := yaml.Unmarshal([]byte(expandedContent), &f.Data); err != nil {
-		return errors.Wrapf(err, "failed to parse YAML file: %s", expandedPath)
+	if f.DecodeHook != nil {
+		_, err = decodeWithHook(content, yamlFormat, f.DecodeHook, data)
+	} else {
+		err = yaml.Unmarshal([]byte(content), data)
+	}
+
+	if err != nil {
+		return errors.Wrapf(newParseError(err, content, f.ErrorSnippet), "failed to parse YAML file: %s", expandEnv(f.FilePath))
 	}
 
 	return nil
@@ -98,3 +149,87 @@ func (f *YAMLFile[T]) parseYAMLFile() error {
 func (f *YAMLFile[T]) Reload() error {
 	return f.parseYAMLFile()
 }
+
+// Get returns the parsed configuration data.
+func (f *YAMLFile[T]) Get() T {
+	return f.Data
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns FilePath, or Data encoded as YAML when MarshalData is set.
+func (f YAMLFile[T]) MarshalText() ([]byte, error) {
+	return marshalText(f.FilePath, f.Data, f.MarshalData, fileFormats[".yaml"])
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It returns FilePath as a JSON string, or Data when MarshalData is set.
+func (f YAMLFile[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(f.FilePath, f.Data, f.MarshalData)
+}
+
+// ReloadIfChanged reloads the YAML configuration file only when its content
+// (after environment variable expansion) changed since the last load.
+// It reports whether the content changed. When the content fails to decode, the
+// previous Data is kept and the content is loaded again by the next call.
+func (f *YAMLFile[T]) ReloadIfChanged() (bool, error) {
+	if f.FilePath == "" {
+		return false, nil
+	}
+
+	content, err := f.readYAMLFile()
+	if err != nil {
+		return false, err
+	}
+
+	sum := checksum(content)
+	if sum == f.sum {
+		return false, nil
+	}
+
+	return true, loadDecoded(&f.Data, &f.sum, content, f.decodeYAML)
+}
+
+// StartPolling checks the file every interval until ctx is done and calls
+// ReloadIfChanged once a change has settled for the debounce duration (see
+// WithReloadDebounce), so a burst of writes results in a single reload.
+// onChange is called with a nil error after the content changed and was reloaded,
+// and with the error when reloading fails. Data is updated from the polling goroutine,
+// so readers must synchronize with onChange.
+// The returned channel is closed once polling has stopped.
+func (f *YAMLFile[T]) StartPolling(
+	ctx context.Context,
+	interval time.Duration,
+	onChange func(err error),
+	opts ...PollOption,
+) <-chan struct{} {
+	return poll(ctx, f, onChange, newPollConfig(interval, opts))
+}
+
+// peek reads the file and reports the checksum of its content and whether it
+// differs from the loaded content, without decoding it.
+func (f *YAMLFile[T]) peek() ([sha256.Size]byte, bool, error) {
+	content, err := f.readYAMLFile()
+	if err != nil {
+		return [sha256.Size]byte{}, false, err
+	}
+
+	sum := checksum(content)
+
+	return sum, sum != f.sum, nil
+}
+
+// OnReload registers fn to be called by ReloadOnSignal after a reload changed
+// the content (with a nil error) or failed (with the error).
+// Callbacks must be registered before ReloadOnSignal is called.
+func (f *YAMLFile[T]) OnReload(fn func(err error)) {
+	f.onReload = append(f.onReload, fn)
+}
+
+// ReloadOnSignal calls ReloadIfChanged every time one of sig is received until ctx is done.
+// It listens for SIGHUP when no signal is given and stops handling the signals once
+// ctx is done, restoring their default behavior unless something else handles them.
+// The OnReload callbacks are invoked from the signal goroutine, so readers of Data
+// must synchronize with them. The returned channel is closed once it has stopped.
+func (f *YAMLFile[T]) ReloadOnSignal(ctx context.Context, sig ...os.Signal) <-chan struct{} {
+	return reloadOnSignal(ctx, sig, f.ReloadIfChanged, f.onReload)
+}
@@ -4,6 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/jkaveri/goconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type TestYAMLConfig struct {
@@ -163,3 +167,49 @@ version: invalid`
 		}
 	})
 }
+
+func TestYAMLFileLoad(t *testing.T) {
+	type db struct {
+		Host     string `yaml:"host"`
+		Password string `yaml:"password"`
+	}
+
+	type services struct {
+		Cache *YAMLFile[db]
+	}
+
+	type config struct {
+		Name     string
+		Port     int
+		DB       YAMLFile[db]
+		Services services
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db.yaml"), []byte("host: ${E2E_DB_HOST}\npassword: $E2E_DB_PASSWORD\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cache.yaml"), []byte("host: ${E2E_NAME}-cache\n"), 0o600))
+
+	t.Setenv("E2E_NAME", "api")
+	t.Setenv("E2E_PORT", "8080")
+	t.Setenv("E2E_DIR", dir)
+	t.Setenv("E2E_DB", "${E2E_DIR}/db.yaml")
+	t.Setenv("E2E_DB_HOST", "db.internal")
+	t.Setenv("E2E_DB_PASSWORD", "hunter2")
+	t.Setenv("E2E_SERVICES_CACHE", filepath.Join(dir, "cache.yaml"))
+
+	var cfg config
+	require.NoError(t, goconfig.New(goconfig.WithPrefix("E2E")).Load(&cfg))
+
+	assert.Equal(t, "api", cfg.Name)
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, db{Host: "db.internal", Password: "hunter2"}, cfg.DB.Data)
+	assert.Equal(t, filepath.Join(dir, "db.yaml"), expandEnv(cfg.DB.FilePath))
+
+	if assert.NotNil(t, cfg.Services.Cache) {
+		assert.Equal(t, "api-cache", cfg.Services.Cache.Data.Host)
+	}
+
+	t.Setenv("E2E_DB_HOST", "db2.internal")
+	require.NoError(t, cfg.DB.Reload())
+	assert.Equal(t, "db2.internal", cfg.DB.Get().Host, "content is expanded again on reload")
+}
@@ -0,0 +1,37 @@
+package goconfig
+
+import (
+	"context"
+	"reflect"
+)
+
+// TextUnmarshalerContext is implemented by types that need a context to parse
+// their value, e.g. to decrypt a secret with a key carried by the context.
+// It takes precedence over encoding.TextUnmarshaler; the context is the one
+// given to LoadContext, or context.Background() for Load.
+type TextUnmarshalerContext interface {
+	UnmarshalTextCtx(ctx context.Context, data []byte) error
+}
+
+// LoadContext loads environment variables into the provided struct like Load,
+// and passes ctx to the fields implementing TextUnmarshalerContext.
+func (c *Loader) LoadContext(ctx context.Context, s any) error {
+	l := *c
+	l.ctx = ctx
+
+	return l.Load(s)
+}
+
+// context returns the context of the current load.
+func (c *Loader) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
+}
+
+// isTextUnmarshalerContextType reports whether a pointer to t implements TextUnmarshalerContext.
+func (*Loader) isTextUnmarshalerContextType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(reflect.TypeOf((*TextUnmarshalerContext)(nil)).Elem())
+}
@@ -0,0 +1,63 @@
+package goconfig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type cipherKey struct{}
+
+// sealedSecret is decoded with a key carried by the context.
+type sealedSecret string
+
+func (s *sealedSecret) UnmarshalTextCtx(ctx context.Context, data []byte) error {
+	key, ok := ctx.Value(cipherKey{}).(string)
+	if !ok {
+		return errors.New("no cipher key in context")
+	}
+
+	*s = sealedSecret(strings.TrimPrefix(string(data), key+":"))
+
+	return nil
+}
+
+// plainSecret only implements encoding.TextUnmarshaler.
+type plainSecret string
+
+func (s *plainSecret) UnmarshalText(data []byte) error {
+	*s = plainSecret(strings.ToUpper(string(data)))
+	return nil
+}
+
+func TestLoadContext(t *testing.T) {
+	type Config struct {
+		DBPassword sealedSecret
+		APIToken   *sealedSecret
+		Fallback   plainSecret
+	}
+
+	t.Setenv("DB_PASSWORD", "k1:s3cret")
+	t.Setenv("API_TOKEN", "k1:token")
+	t.Setenv("FALLBACK", "plain")
+
+	t.Run("context values", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), cipherKey{}, "k1")
+
+		var cfg Config
+		err := New().LoadContext(ctx, &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, sealedSecret("s3cret"), cfg.DBPassword)
+		assert.Equal(t, sealedSecret("token"), *cfg.APIToken)
+		assert.Equal(t, plainSecret("PLAIN"), cfg.Fallback)
+	})
+
+	t.Run("load without context", func(t *testing.T) {
+		err := New().Load(&Config{})
+		assert.ErrorContains(t, err, "cannot set field DB_PASSWORD value: no cipher key in context")
+	})
+}
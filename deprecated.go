@@ -0,0 +1,22 @@
+package goconfig
+
+import (
+	"log"
+	"reflect"
+)
+
+// warnDeprecated reports a value read for a field tagged deprecated:"message"
+// to the handler set with WithDeprecationHandler, or to the standard logger.
+func (c *Loader) warnDeprecated(tf reflect.StructField, envKey string) {
+	message, ok := tf.Tag.Lookup("deprecated")
+	if !ok {
+		return
+	}
+
+	if c.deprecationHandler == nil {
+		log.Printf("goconfig: %s is deprecated: %s", envKey, message)
+		return
+	}
+
+	c.deprecationHandler(envKey, message)
+}
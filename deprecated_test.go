@@ -0,0 +1,74 @@
+package goconfig
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationHandler(t *testing.T) {
+	type Config struct {
+		DBURL    string `env:"DPR_DB_URL"`
+		DBHost   string `env:"DPR_DB_HOST" deprecated:"use DPR_DB_URL instead"`
+		LegacyID int    `alias:"OLD_ID" deprecated:"will be removed in v2"`
+		Name     string
+	}
+
+	type warning struct{ key, message string }
+
+	load := func(t *testing.T) []warning {
+		t.Helper()
+
+		var warnings []warning
+
+		handler := func(key, message string) {
+			warnings = append(warnings, warning{key, message})
+		}
+
+		var cfg Config
+		require.NoError(t, New(WithPrefix("DPR"), WithDeprecationHandler(handler)).Load(&cfg))
+
+		return warnings
+	}
+
+	t.Run("not fired when unset", func(t *testing.T) {
+		t.Setenv("DPR_DB_URL", "postgres://db")
+		t.Setenv("DPR_NAME", "api")
+
+		assert.Empty(t, load(t))
+	})
+
+	t.Run("fired for each deprecated key present", func(t *testing.T) {
+		t.Setenv("DPR_DB_HOST", "db")
+		t.Setenv("DPR_OLD_ID", "7")
+
+		assert.Equal(t, []warning{
+			{"DPR_DB_HOST", "use DPR_DB_URL instead"},
+			{"DPR_OLD_ID", "will be removed in v2"},
+		}, load(t))
+	})
+
+	t.Run("empty value is present", func(t *testing.T) {
+		t.Setenv("DPR_DB_HOST", "")
+
+		assert.Equal(t, []warning{{"DPR_DB_HOST", "use DPR_DB_URL instead"}}, load(t))
+	})
+
+	t.Run("default logs", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		out := log.Writer()
+		log.SetOutput(&buf)
+
+		defer log.SetOutput(out)
+
+		t.Setenv("DPR_DB_HOST", "db")
+
+		var cfg Config
+		require.NoError(t, New(WithPrefix("DPR")).Load(&cfg))
+		assert.Contains(t, buf.String(), "goconfig: DPR_DB_HOST is deprecated: use DPR_DB_URL instead")
+	})
+}
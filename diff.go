@@ -0,0 +1,86 @@
+package goconfig
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/jkaveri/goconfig/internal/deepcopy"
+	"github.com/pkg/errors"
+)
+
+// FieldChange describes a field whose value changed during LoadDiff.
+type FieldChange struct {
+	// Path is the Go field path, e.g. "DB.Host"
+	Path string
+	// Old is the value before loading
+	Old any
+	// New is the value after loading
+	New any
+}
+
+// LoadDiff loads environment variables into s like Load and returns the fields
+// whose values differ from the values s held before, in field order. Nested
+// structs are compared field by field; other values are compared with
+// reflect.DeepEqual. It is meant for incremental reloads of a live config.
+func (c *Loader) LoadDiff(s any) ([]FieldChange, error) {
+	target := reflect.ValueOf(s)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return nil, errors.Errorf("should be a pointer to a struct, got %T", s)
+	}
+
+	before := deepcopy.Value(target.Elem())
+
+	if err := c.Load(s); err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	c.diffStruct(before, target.Elem(), nil, &changes)
+
+	return changes, nil
+}
+
+// diffStruct appends the changed fields between the struct values old and cur.
+func (c *Loader) diffStruct(old, cur reflect.Value, path []string, changes *[]FieldChange) {
+	t := cur.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		tf := t.Field(i)
+		if !tf.IsExported() {
+			continue
+		}
+
+		fieldPath := c.buildFieldPath(tf, path)
+		ov, cv := old.Field(i), cur.Field(i)
+
+		if ft := c.getDirectType(tf.Type); c.isStruct(ft.Kind()) && !c.isLeafType(ft) {
+			if oe, ce, ok := derefPair(ov, cv); ok {
+				c.diffStruct(oe, ce, fieldPath, changes)
+				continue
+			}
+		}
+
+		if !reflect.DeepEqual(ov.Interface(), cv.Interface()) {
+			*changes = append(*changes, FieldChange{
+				Path: strings.Join(fieldPath, "."),
+				Old:  ov.Interface(),
+				New:  cv.Interface(),
+			})
+		}
+	}
+}
+
+// derefPair strips all pointer levels of old and cur, e.g. of a **Struct field.
+// It returns false when one of them is nil at some level, in which case the
+// values are compared as a whole.
+func derefPair(old, cur reflect.Value) (reflect.Value, reflect.Value, bool) {
+	for old.Kind() == reflect.Pointer {
+		if old.IsNil() || cur.IsNil() {
+			return old, cur, false
+		}
+
+		old, cur = old.Elem(), cur.Elem()
+	}
+
+	return old, cur, true
+}
@@ -0,0 +1,97 @@
+package goconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDiff(t *testing.T) {
+	type Cache struct {
+		TTL time.Duration
+	}
+
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		DB     DB
+		Cache  *Cache
+		Tags   []string
+		Region string
+	}
+
+	cfg := Config{
+		DB:     DB{Host: "db-1", Port: 5432},
+		Tags:   []string{"a"},
+		Region: "eu",
+	}
+
+	t.Setenv("DIFF_DB_HOST", "db-2")
+	t.Setenv("DIFF_DB_PORT", "5432")
+	t.Setenv("DIFF_TAGS", "a")
+	t.Setenv("DIFF_CACHE_TTL", "1m")
+
+	loader := New(WithPrefix("DIFF"))
+
+	changes, err := loader.LoadDiff(&cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []FieldChange{
+		{Path: "DB.Host", Old: "db-1", New: "db-2"},
+		{Path: "Cache", Old: (*Cache)(nil), New: &Cache{TTL: time.Minute}},
+	}, changes)
+	assert.Equal(t, "db-2", cfg.DB.Host)
+	assert.Equal(t, "eu", cfg.Region)
+
+	t.Run("nothing changed", func(t *testing.T) {
+		changes, err := loader.LoadDiff(&cfg)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("nested pointer compared by field", func(t *testing.T) {
+		t.Setenv("DIFF_CACHE_TTL", "2m")
+
+		changes, err := loader.LoadDiff(&cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, []FieldChange{
+			{Path: "Cache.TTL", Old: time.Minute, New: 2 * time.Minute},
+		}, changes)
+	})
+
+	t.Run("double pointer compared by field", func(t *testing.T) {
+		type Config struct {
+			DB **DB
+		}
+
+		t.Setenv("DIFF2_DB_HOST", "db-1")
+
+		var cfg Config
+		loader := New(WithPrefix("DIFF2"))
+
+		changes, err := loader.LoadDiff(&cfg)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "DB", changes[0].Path)
+
+		t.Setenv("DIFF2_DB_HOST", "db-2")
+
+		changes, err = loader.LoadDiff(&cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, []FieldChange{
+			{Path: "DB.Host", Old: "db-1", New: "db-2"},
+		}, changes)
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		_, err := loader.LoadDiff(cfg)
+		assert.Error(t, err)
+	})
+}
@@ -7,6 +7,7 @@
 //   - Support for nested structs and pointers
 //   - Customizable field name transformation
 //   - Support for various data types (string, bool, int, uint, float, duration, slices, maps)
+//   - Maps from JSON or from "key=value" lists with the format:"kv" tag
 //   - Configurable prefix and separators
 //   - Tag-based field mapping with env and alias tags
 //   - Anonymous struct embedding support
@@ -0,0 +1,256 @@
+package goconfig
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRedacted replaces the value of fields tagged sensitive:"true" in DumpYAML.
+const DefaultRedacted = "[REDACTED]"
+
+// DumpOption customizes DumpYAML.
+type DumpOption func(*dumper)
+
+// WithRedacted sets the text that replaces sensitive values in DumpYAML.
+func WithRedacted(placeholder string) DumpOption {
+	return func(d *dumper) {
+		d.redacted = placeholder
+	}
+}
+
+// WithRedactor sets the function masking sensitive values in DumpYAML, e.g. to
+// keep the last 4 characters of a token. It receives the Go field path, such as
+// "DB.Password" (embedded structs add no segment, map keys and slice indexes do,
+// e.g. "Servers[0].Token"), and the value as it would be dumped: the text of a
+// scalar, "" for nil, or fmt.Sprint of a struct, map or slice. It takes
+// precedence over WithRedacted.
+func WithRedactor(redact func(fieldPath, value string) string) DumpOption {
+	return func(d *dumper) {
+		d.redactor = redact
+	}
+}
+
+// DumpYAML marshals a loaded struct to YAML for debugging, e.g. to compare the
+// effective configuration against expectations. Fields are written in declaration
+// order under their yaml tag name or their Go name, fields of embedded structs
+// are inlined and map keys are sorted. Values implementing encoding.TextMarshaler
+// or fmt.Stringer (e.g. time.Duration) are written as text. The value of every
+// field tagged sensitive:"true" is replaced by DefaultRedacted, or masked by the
+// function set with WithRedactor.
+func DumpYAML(s any, opts ...DumpOption) ([]byte, error) {
+	d := &dumper{redacted: DefaultRedacted}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	node, err := d.node(reflect.ValueOf(s), "")
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(node)
+}
+
+// dumper converts values to YAML nodes.
+type dumper struct {
+	redacted string
+	redactor func(fieldPath, value string) string
+}
+
+// node converts v to a YAML node; path is the Go field path of v.
+func (d *dumper) node(v reflect.Value, path string) (*yaml.Node, error) {
+	if !v.IsValid() {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return d.node(reflect.Value{}, path)
+	}
+
+	if n, ok, err := d.textNode(v); ok || err != nil {
+		return n, err
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return d.node(v.Elem(), path)
+	case reflect.Struct:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		return n, d.appendFields(n, v, path)
+	case reflect.Map:
+		return d.mapNode(v, path)
+	case reflect.Slice, reflect.Array:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+
+		for i := 0; i < v.Len(); i++ {
+			elem, err := d.node(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+
+			n.Content = append(n.Content, elem)
+		}
+
+		return n, nil
+	default:
+		n := &yaml.Node{}
+		if err := n.Encode(v.Interface()); err != nil {
+			return nil, errors.Wrapf(err, "cannot dump %s value", v.Type())
+		}
+
+		return n, nil
+	}
+}
+
+// textNode writes values implementing encoding.TextMarshaler or fmt.Stringer as text.
+func (*dumper) textNode(v reflect.Value) (*yaml.Node, bool, error) {
+	if !v.CanInterface() {
+		return nil, false, nil
+	}
+
+	// methods with a pointer receiver are only reachable through the address
+	if v.Kind() != reflect.Pointer && v.CanAddr() {
+		v = v.Addr()
+	}
+
+	switch x := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := x.MarshalText()
+		if err != nil {
+			return nil, true, errors.Wrapf(err, "cannot dump %s value", v.Type())
+		}
+
+		return stringNode(string(text)), true, nil
+	case fmt.Stringer:
+		return stringNode(x.String()), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// appendFields appends the exported fields of a struct to the mapping node n.
+func (d *dumper) appendFields(n *yaml.Node, v reflect.Value, path string) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		tf := t.Field(i)
+		if !tf.IsExported() {
+			continue
+		}
+
+		name := tf.Name
+		if tag, ok := tf.Tag.Lookup("yaml"); ok {
+			if tag, _, _ = strings.Cut(tag, ","); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+		}
+
+		fv := v.Field(i)
+
+		if tf.Anonymous && reflect.Indirect(fv).Kind() == reflect.Struct {
+			if fv.Kind() == reflect.Pointer && fv.IsNil() {
+				continue
+			}
+
+			if err := d.appendFields(n, reflect.Indirect(fv), path); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var (
+			value     *yaml.Node
+			err       error
+			fieldPath = joinFieldPath(path, tf.Name)
+		)
+
+		if sensitive, _ := strconv.ParseBool(tf.Tag.Get("sensitive")); sensitive {
+			value, err = d.redact(fv, fieldPath)
+		} else {
+			value, err = d.node(fv, fieldPath)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		n.Content = append(n.Content, stringNode(name), value)
+	}
+
+	return nil
+}
+
+// redact returns the node replacing the value of a sensitive field.
+func (d *dumper) redact(v reflect.Value, path string) (*yaml.Node, error) {
+	if d.redactor == nil {
+		return stringNode(d.redacted), nil
+	}
+
+	n, err := d.node(v, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var text string
+
+	switch {
+	case n.Tag == "!!null":
+	case n.Kind == yaml.ScalarNode:
+		text = n.Value
+	default:
+		text = fmt.Sprint(reflect.Indirect(v).Interface())
+	}
+
+	return stringNode(d.redactor(path, text)), nil
+}
+
+// mapNode converts a map to a mapping node with sorted keys.
+func (d *dumper) mapNode(v reflect.Value, path string) (*yaml.Node, error) {
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		entries = append(entries, entry{key: fmt.Sprint(iter.Key().Interface()), value: iter.Value()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	n := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, e := range entries {
+		value, err := d.node(e.value, joinFieldPath(path, e.key))
+		if err != nil {
+			return nil, err
+		}
+
+		n.Content = append(n.Content, stringNode(e.key), value)
+	}
+
+	return n, nil
+}
+
+// joinFieldPath appends name to a dotted field path.
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
@@ -0,0 +1,116 @@
+package goconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpYAML(t *testing.T) {
+	type Credentials struct {
+		User     string
+		Password string `sensitive:"true"`
+	}
+
+	type DB struct {
+		Credentials
+		Host    string `yaml:"host"`
+		Port    int    `yaml:"port"`
+		Timeout time.Duration
+		Replica *DB
+	}
+
+	type Config struct {
+		Name    string
+		DB      DB
+		Labels  map[string]string
+		Tokens  map[string]string `sensitive:"true"`
+		Origins []string
+		Ignored string `yaml:"-"`
+		secret  string
+	}
+
+	cfg := Config{
+		Name: "api",
+		DB: DB{
+			Credentials: Credentials{User: "admin", Password: "hunter2"},
+			Host:        "localhost",
+			Port:        5432,
+			Timeout:     5 * time.Second,
+		},
+		Labels:  map[string]string{"team": "core", "env": "prod"},
+		Tokens:  map[string]string{"github": "ghp_xxx"},
+		Origins: []string{"a.example", "b.example"},
+		Ignored: "ignored",
+		secret:  "unexported",
+	}
+
+	out, err := DumpYAML(&cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, `Name: api
+DB:
+    User: admin
+    Password: '[REDACTED]'
+    host: localhost
+    port: 5432
+    Timeout: 5s
+    Replica: null
+Labels:
+    env: prod
+    team: core
+Tokens: '[REDACTED]'
+Origins:
+    - a.example
+    - b.example
+`, string(out))
+
+	t.Run("custom placeholder", func(t *testing.T) {
+		out, err := DumpYAML(Credentials{Password: "hunter2"}, WithRedacted("***"))
+		require.NoError(t, err)
+		assert.Equal(t, "User: \"\"\nPassword: '***'\n", string(out))
+	})
+
+	t.Run("custom redactor", func(t *testing.T) {
+		type Server struct {
+			Token string `sensitive:"true"`
+		}
+
+		type Secrets struct {
+			Credentials
+			Servers []Server
+			Keys    map[string]string `sensitive:"true"`
+			Pin     *int              `sensitive:"true"`
+		}
+
+		var paths []string
+
+		lastFour := func(path, value string) string {
+			paths = append(paths, path)
+
+			if len(value) <= 4 {
+				return "****"
+			}
+
+			return "****" + value[len(value)-4:]
+		}
+
+		out, err := DumpYAML(Secrets{
+			Credentials: Credentials{User: "admin", Password: "hunter2"},
+			Servers:     []Server{{Token: "ghp_abcdef123456"}},
+			Keys:        map[string]string{"a": "1"},
+		}, WithRedactor(lastFour), WithRedacted("ignored"))
+		require.NoError(t, err)
+
+		assert.Equal(t, `User: admin
+Password: '****ter2'
+Servers:
+    - Token: '****3456'
+Keys: '****a:1]'
+Pin: '****'
+`, string(out))
+		assert.Equal(t, []string{"Password", "Servers[0].Token", "Keys", "Pin"}, paths)
+	})
+}
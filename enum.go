@@ -0,0 +1,41 @@
+package goconfig
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// enumParser returns a parser mapping the names of an integer enum type to their values.
+func enumParser(t reflect.Type, names map[string]int64) func(raw string) (any, error) {
+	valid := make([]string, 0, len(names))
+	for name := range names {
+		valid = append(valid, name)
+	}
+
+	sort.Strings(valid)
+
+	return func(raw string) (any, error) {
+		n, ok := names[raw]
+		if !ok {
+			return nil, errors.Errorf("unknown %s %q, valid values are: %s", t, raw, strings.Join(valid, ", "))
+		}
+
+		v := reflect.New(t).Elem()
+
+		switch {
+		case !v.CanInt() && !v.CanUint():
+			return nil, errors.Errorf("enum type %s is not an integer type", t)
+		case v.CanInt() && !v.OverflowInt(n):
+			v.SetInt(n)
+		case v.CanUint() && n >= 0 && !v.OverflowUint(uint64(n)):
+			v.SetUint(uint64(n))
+		default:
+			return nil, errors.Errorf("value %d of %q overflows %s", n, raw, t)
+		}
+
+		return v.Interface(), nil
+	}
+}
@@ -0,0 +1,69 @@
+package goconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type deployEnv int
+
+const (
+	envDevelopment deployEnv = iota
+	envStaging
+	envProduction
+)
+
+func (e deployEnv) String() string {
+	return [...]string{"development", "staging", "production"}[e]
+}
+
+type logLevel uint8
+
+func TestWithEnum(t *testing.T) {
+	type Config struct {
+		DeployEnv  deployEnv
+		Fallbacks  []deployEnv
+		Verbosity  *logLevel
+		QuietLevel logLevel
+	}
+
+	loader := New(
+		WithEnum(reflect.TypeOf(deployEnv(0)), map[string]int64{
+			envDevelopment.String(): int64(envDevelopment),
+			envStaging.String():     int64(envStaging),
+			envProduction.String():  int64(envProduction),
+		}),
+		WithEnum(reflect.TypeOf(logLevel(0)), map[string]int64{"info": 1, "debug": 2, "huge": 300}),
+	)
+
+	t.Run("names", func(t *testing.T) {
+		t.Setenv("DEPLOY_ENV", "production")
+		t.Setenv("FALLBACKS", "staging,development")
+		t.Setenv("VERBOSITY", "debug")
+
+		var cfg Config
+		err := loader.Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, envProduction, cfg.DeployEnv)
+		assert.Equal(t, []deployEnv{envStaging, envDevelopment}, cfg.Fallbacks)
+		assert.Equal(t, logLevel(2), *cfg.Verbosity)
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		t.Setenv("DEPLOY_ENV", "prod")
+
+		err := loader.Load(&Config{})
+		assert.ErrorContains(t, err, "DEPLOY_ENV")
+		assert.ErrorContains(t, err, `unknown goconfig.deployEnv "prod", valid values are: development, production, staging`)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		t.Setenv("QUIET_LEVEL", "huge")
+
+		err := loader.Load(&Config{})
+		assert.ErrorContains(t, err, `value 300 of "huge" overflows goconfig.logLevel`)
+	})
+}
@@ -0,0 +1,28 @@
+package goconfig
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// applyGlobalDefault sets a field whose variable is not set to the value the
+// WithGlobalDefault function returns for its path. Fields that already hold a
+// default, from WithDefaults or the loaded struct, are left untouched. The value
+// is parsed like the value of a variable.
+func (c *Loader) applyGlobalDefault(tf reflect.StructField, vf reflect.Value, fieldPath, envKey string) error {
+	if c.globalDefault == nil || !vf.IsZero() {
+		return nil
+	}
+
+	raw, ok := c.globalDefault(fieldPath)
+	if !ok {
+		return nil
+	}
+
+	if _, err := c.setValue(tf, vf, raw); err != nil {
+		return errors.Wrapf(err, "cannot set field %s default value", envKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package goconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalDefault(t *testing.T) {
+	type Service struct {
+		URL     string
+		Retries int
+	}
+
+	type Config struct {
+		AuthURL  string
+		Billing  Service
+		Search   Service
+		Timeout  int
+		Name     string `required:"true"`
+		Disabled bool
+	}
+
+	baseURL := func(fieldPath string) (string, bool) {
+		switch {
+		case fieldPath == "AuthURL":
+			return "https://auth.example.com", true
+		case strings.HasSuffix(fieldPath, ".URL"):
+			return "https://" + strings.ToLower(strings.TrimSuffix(fieldPath, ".URL")) + ".example.com", true
+		case strings.HasSuffix(fieldPath, ".Retries"):
+			return "3", true
+		case fieldPath == "Name":
+			return "app", true
+		default:
+			return "", false
+		}
+	}
+
+	t.Setenv("GD_SEARCH_URL", "http://localhost:9200")
+
+	var paths []string
+
+	record := func(fieldPath string) (string, bool) {
+		paths = append(paths, fieldPath)
+		return baseURL(fieldPath)
+	}
+
+	cfg := Config{Timeout: 30}
+	require.NoError(t, New(WithPrefix("GD"), WithGlobalDefault(record)).Load(&cfg))
+
+	assert.Equal(t, Config{
+		AuthURL: "https://auth.example.com",
+		Billing: Service{URL: "https://billing.example.com", Retries: 3},
+		Search:  Service{URL: "http://localhost:9200", Retries: 3},
+		Timeout: 30,
+		Name:    "app",
+	}, cfg)
+
+	// set variables, nested structs and fields holding a default are skipped
+	assert.Equal(t, []string{"AuthURL", "Billing.URL", "Billing.Retries", "Search.Retries", "Name", "Disabled"}, paths)
+
+	t.Run("invalid default", func(t *testing.T) {
+		err := New(WithPrefix("GD"), WithGlobalDefault(func(fieldPath string) (string, bool) {
+			return "many", fieldPath == "Timeout"
+		})).Load(&Config{Name: "app"})
+
+		assert.ErrorContains(t, err, "cannot set field GD_TIMEOUT default value")
+	})
+
+	t.Run("required without default", func(t *testing.T) {
+		err := New(WithPrefix("GD"), WithGlobalDefault(func(string) (string, bool) {
+			return "", false
+		})).Load(&Config{})
+
+		assert.ErrorIs(t, err, ErrMissingRequired)
+	})
+}
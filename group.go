@@ -0,0 +1,91 @@
+package goconfig
+
+import (
+	"bufio"
+	stderrors "errors"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPartialGroup is returned when WithRequiredGroups is set and only some
+// fields of a group are present in the environment.
+var ErrPartialGroup = errors.New("partially set group")
+
+// loadState holds what a single Load call collects while walking the struct.
+type loadState struct {
+	// groups are the field groups in the order they were first seen
+	groups []*fieldGroup
+	// envIndex maps normalized env keys to their values, see WithEnvKeyNormalizer
+	envIndex map[string]string
+	// stdin buffers the reader of WithStdin, see readStdin
+	stdin *bufio.Reader
+	// errs are the errors collected with WithErrorAggregation
+	errs []error
+	// lookedUp are the keys read from the environment, see WithStrictPrefix
+	lookedUp map[string]bool
+	// layers are the sources consulted for every key, see lookupKey
+	layers []layer
+}
+
+// fieldGroup tracks which members of a group tag were found.
+type fieldGroup struct {
+	name    string
+	found   []string
+	missing []string
+}
+
+// addGroupMember records whether the field with the env key was found for each
+// group of the comma separated group tag.
+func (st *loadState) addGroupMember(groups, envKey string, found bool) {
+	for _, name := range strings.Split(groups, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		g := st.group(name)
+		if found {
+			g.found = append(g.found, envKey)
+		} else {
+			g.missing = append(g.missing, envKey)
+		}
+	}
+}
+
+func (st *loadState) group(name string) *fieldGroup {
+	for _, g := range st.groups {
+		if g.name == name {
+			return g
+		}
+	}
+
+	g := &fieldGroup{name: name}
+	st.groups = append(st.groups, g)
+
+	return g
+}
+
+// checkGroups returns an ErrPartialGroup error for every group that has both
+// found and missing members.
+func (c *Loader) checkGroups() error {
+	if !c.requiredGroups {
+		return nil
+	}
+
+	var errs []error
+
+	for _, g := range c.state.groups {
+		if len(g.found) > 0 && len(g.missing) > 0 {
+			errs = append(errs, errors.Wrapf(
+				ErrPartialGroup,
+				"group %s: missing %s (set: %s)",
+				g.name,
+				strings.Join(g.missing, ", "),
+				strings.Join(g.found, ", "),
+			))
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredGroups(t *testing.T) {
+	type TLS struct {
+		CertFile string `group:"tls"`
+		KeyFile  string `group:"tls"`
+	}
+
+	type Config struct {
+		DBUser     string `group:"db"`
+		DBPassword string `group:"db"`
+		DBHost     string `group:"db"`
+		TLS        TLS
+		Debug      bool
+	}
+
+	loader := New(WithPrefix("GRP"), WithRequiredGroups())
+
+	t.Run("all present", func(t *testing.T) {
+		t.Setenv("GRP_DB_USER", "admin")
+		t.Setenv("GRP_DB_PASSWORD", "secret")
+		t.Setenv("GRP_DB_HOST", "localhost")
+
+		var cfg Config
+		assert.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, "localhost", cfg.DBHost)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		t.Setenv("GRP_DEBUG", "true")
+
+		assert.NoError(t, loader.Load(&Config{}))
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		t.Setenv("GRP_DB_USER", "admin")
+		t.Setenv("GRP_TLS_KEY_FILE", "server.key")
+
+		err := loader.Load(&Config{})
+
+		assert.ErrorIs(t, err, ErrPartialGroup)
+		assert.ErrorContains(t, err, "group db: missing GRP_DB_PASSWORD, GRP_DB_HOST (set: GRP_DB_USER)")
+		assert.ErrorContains(t, err, "group tls: missing GRP_TLS_CERT_FILE (set: GRP_TLS_KEY_FILE)")
+	})
+
+	t.Run("not checked without option", func(t *testing.T) {
+		t.Setenv("GRP_DB_USER", "admin")
+
+		assert.NoError(t, New(WithPrefix("GRP")).Load(&Config{}))
+	})
+}
@@ -0,0 +1,84 @@
+// Package deepcopy copies values with reflection so the copy shares no
+// pointers, slices or maps with the original.
+package deepcopy
+
+import "reflect"
+
+// Copy returns a deep copy of v.
+// Unexported struct fields are copied shallowly since they cannot be set
+// through reflection. Cyclic values are not supported.
+func Copy[T any](v T) T {
+	rv := reflect.ValueOf(&v).Elem()
+	out := reflect.New(rv.Type()).Elem()
+	out.Set(Value(rv))
+
+	return out.Interface().(T)
+}
+
+// Value returns a deep copy of v.
+func Value(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(Value(v.Elem()))
+
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.New(v.Type()).Elem()
+		out.Set(Value(v.Elem()))
+
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+
+		for i := 0; i < v.NumField(); i++ {
+			if f := out.Field(i); f.CanSet() {
+				f.Set(Value(v.Field(i)))
+			}
+		}
+
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(Value(v.Index(i)))
+		}
+
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(Value(v.Index(i)))
+		}
+
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), Value(iter.Value()))
+		}
+
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,56 @@
+package deepcopy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type inner struct {
+	Name string
+}
+
+type outer struct {
+	Ptr    *inner
+	Slice  []inner
+	Map    map[string][]int
+	Any    any
+	Array  [2]*inner
+	hidden int
+}
+
+func TestCopy(t *testing.T) {
+	orig := outer{
+		Ptr:    &inner{Name: "ptr"},
+		Slice:  []inner{{Name: "slice"}},
+		Map:    map[string][]int{"a": {1, 2}},
+		Any:    &inner{Name: "any"},
+		Array:  [2]*inner{{Name: "array"}},
+		hidden: 42,
+	}
+
+	cp := Copy(orig)
+	assert.Equal(t, orig, cp)
+
+	cp.Ptr.Name = "changed"
+	cp.Slice[0].Name = "changed"
+	cp.Map["a"][0] = 100
+	cp.Any.(*inner).Name = "changed"
+	cp.Array[0].Name = "changed"
+
+	assert.Equal(t, "ptr", orig.Ptr.Name)
+	assert.Equal(t, "slice", orig.Slice[0].Name)
+	assert.Equal(t, []int{1, 2}, orig.Map["a"])
+	assert.Equal(t, "any", orig.Any.(*inner).Name)
+	assert.Equal(t, "array", orig.Array[0].Name)
+}
+
+func TestCopyNil(t *testing.T) {
+	var orig outer
+
+	cp := Copy(orig)
+	assert.Nil(t, cp.Ptr)
+	assert.Nil(t, cp.Slice)
+	assert.Nil(t, cp.Map)
+	assert.Nil(t, cp.Any)
+}
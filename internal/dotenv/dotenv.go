@@ -0,0 +1,162 @@
+// Package dotenv parses dotenv files made of KEY=VALUE lines.
+package dotenv
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Option configures the parser.
+type Option func(*parser)
+
+// WithCommentStrip strips trailing comments from unquoted values:
+// a "#" at the start of the value or preceded by a space or tab starts a comment.
+// Without it, everything after "=" is part of an unquoted value.
+// A "#" inside a quoted value is always preserved.
+func WithCommentStrip() Option {
+	return func(p *parser) {
+		p.stripComments = true
+	}
+}
+
+type parser struct {
+	stripComments bool
+}
+
+// Parse reads KEY=VALUE lines from r. Blank lines and lines starting with "#"
+// are ignored, and an "export " before the key is dropped. Values can be wrapped
+// in single or double quotes; a quoted value ends at its closing quote and may
+// only be followed by spaces or a comment. Double quoted values unescape \n, \r,
+// \t, \" and \\; single quoted values are kept as they are.
+// When a key appears several times the last value wins.
+func Parse(r io.Reader, opts ...Option) (map[string]string, error) {
+	p := &parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	env := map[string]string{}
+	scanner := bufio.NewScanner(r)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := p.parseLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNo)
+		}
+
+		env[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "cannot read dotenv")
+	}
+
+	return env, nil
+}
+
+func (p *parser) parseLine(line string) (key, value string, err error) {
+	key, raw, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", errors.Errorf("missing '=' in %q", line)
+	}
+
+	key = strings.TrimSpace(key)
+	if rest, ok := strings.CutPrefix(key, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		key = strings.TrimSpace(rest)
+	}
+
+	if key == "" {
+		return "", "", errors.Errorf("missing key in %q", line)
+	}
+
+	raw = strings.TrimSpace(raw)
+
+	if raw != "" && (raw[0] == '"' || raw[0] == '\'') {
+		value, err = parseQuoted(raw)
+		return key, value, err
+	}
+
+	if p.stripComments {
+		raw = stripComment(raw)
+	}
+
+	return key, raw, nil
+}
+
+// parseQuoted returns the content of a quoted value and checks that only
+// spaces or a comment follow the closing quote.
+func parseQuoted(raw string) (string, error) {
+	quote := raw[0]
+
+	end := 1
+	for end < len(raw) && raw[end] != quote {
+		if quote == '"' && raw[end] == '\\' {
+			end++
+		}
+		end++
+	}
+
+	if end >= len(raw) {
+		return "", errors.Errorf("unterminated quoted value %s", raw)
+	}
+
+	if rest := strings.TrimSpace(raw[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", errors.Errorf("unexpected %q after quoted value", rest)
+	}
+
+	if quote == '"' {
+		return unescape(raw[1:end]), nil
+	}
+
+	return raw[1:end], nil
+}
+
+// escapes maps the characters following a backslash in a double quoted value
+// to the characters they stand for.
+var escapes = map[byte]byte{'n': '\n', 'r': '\r', 't': '\t', '"': '"', '\\': '\\'}
+
+// unescape replaces the escape sequences of a double quoted value. A backslash
+// before any other character is kept.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			if c, ok := escapes[s[i+1]]; ok {
+				_ = b.WriteByte(c)
+				i++
+
+				continue
+			}
+		}
+
+		_ = b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// stripComment removes a trailing "# comment" from an unquoted value.
+func stripComment(raw string) string {
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && (i == 0 || raw[i-1] == ' ' || raw[i-1] == '\t') {
+			return strings.TrimSpace(raw[:i])
+		}
+	}
+
+	return raw
+}
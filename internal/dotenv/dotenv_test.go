@@ -0,0 +1,93 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	input := `
+# full line comment
+HOST=localhost
+PORT = 8080
+EMPTY=
+SINGLE='single # quoted'
+DOUBLE="double # quoted" # trailing comment
+URL=http://example.com/#anchor
+INLINE=value # comment
+`
+
+	t.Run("without comment strip", func(t *testing.T) {
+		env, err := Parse(strings.NewReader(input))
+		assert.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			"HOST":   "localhost",
+			"PORT":   "8080",
+			"EMPTY":  "",
+			"SINGLE": "single # quoted",
+			"DOUBLE": "double # quoted",
+			"URL":    "http://example.com/#anchor",
+			"INLINE": "value # comment",
+		}, env)
+	})
+
+	t.Run("with comment strip", func(t *testing.T) {
+		env, err := Parse(strings.NewReader(input), WithCommentStrip())
+		assert.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			"HOST":   "localhost",
+			"PORT":   "8080",
+			"EMPTY":  "",
+			"SINGLE": "single # quoted",
+			"DOUBLE": "double # quoted",
+			"URL":    "http://example.com/#anchor",
+			"INLINE": "value",
+		}, env)
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing equal", input: "HOST"},
+		{name: "missing key", input: "=value"},
+		{name: "unterminated quote", input: `HOST="localhost`},
+		{name: "text after quote", input: `HOST="local" host`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			assert.ErrorContains(t, err, "line 1")
+		})
+	}
+}
+
+func TestParseExportAndEscapes(t *testing.T) {
+	input := `
+export HOST=localhost
+export	PORT="8080"
+exported=value
+EXPORT_DIR=/tmp
+CERT="line1\nline2\tend \"quoted\" \\ \d"
+RAW='line1\nline2'
+`
+
+	env, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"HOST":       "localhost",
+		"PORT":       "8080",
+		"exported":   "value",
+		"EXPORT_DIR": "/tmp",
+		"CERT":       "line1\nline2\tend \"quoted\" \\ \\d",
+		"RAW":        `line1\nline2`,
+	}, env)
+}
@@ -0,0 +1,20 @@
+package goconfig
+
+// LoadFile loads the variables of a dotenv file at path, made of KEY=VALUE
+// lines, into s. Lines starting with "#", trailing " # comments" of unquoted
+// values and an "export " before the key are ignored; values can be single or
+// double quoted, with \n and other escapes unescaped in double quotes.
+// A variable set in the environment overrides the entry of the file.
+//
+// The environment and the file are added with WithSources after options, so
+// options such as WithPrefix apply as with Load, sources given in options with
+// WithSources or WithLookupSource are consulted before them, and a WithKVSource
+// is consulted around them as usual.
+func LoadFile(s any, path string, options ...Option) error {
+	file, err := DotenvSource(path)
+	if err != nil {
+		return err
+	}
+
+	return New(append(options[:len(options):len(options)], WithSources(EnvSource{}, file))...).Load(s)
+}
@@ -0,0 +1,83 @@
+package goconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		Name  string
+		Tags  []string
+		Cert  string
+		Token string
+		DB    DB
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(`# local settings
+export LF_NAME=app # the app name
+LF_TAGS='a,b'
+LF_CERT="-----BEGIN-----\nabc\n-----END-----"
+LF_TOKEN=from-file
+LF_DB_HOST=localhost
+LF_DB_PORT=5432
+`), 0o600))
+
+	t.Setenv("LF_TOKEN", "from-env")
+	t.Setenv("LF_DB_PORT", "6543")
+
+	var cfg Config
+	require.NoError(t, LoadFile(&cfg, path, WithPrefix("LF")))
+
+	assert.Equal(t, Config{
+		Name:  "app",
+		Tags:  []string{"a", "b"},
+		Cert:  "-----BEGIN-----\nabc\n-----END-----",
+		Token: "from-env",
+		DB:    DB{Host: "localhost", Port: 6543},
+	}, cfg)
+
+	t.Run("sources of options come first", func(t *testing.T) {
+		var cfg Config
+
+		err := LoadFile(&cfg, path, WithPrefix("LF"), WithSources(MapSource{"LF_NAME": "from-option", "LF_TOKEN": "from-option"}))
+		require.NoError(t, err)
+
+		assert.Equal(t, "from-option", cfg.Name)
+		assert.Equal(t, "from-option", cfg.Token)
+		assert.Equal(t, 6543, cfg.DB.Port, "the environment is still read")
+		assert.Equal(t, "localhost", cfg.DB.Host, "the file is still read")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := LoadFile(&Config{}, filepath.Join(t.TempDir(), ".env"))
+		assert.ErrorContains(t, err, "cannot read env file")
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte("LF_NAME=app\nLF_PORT\n"), 0o600))
+
+		err := LoadFile(&Config{}, path)
+		assert.ErrorContains(t, err, "cannot parse env file")
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte("LFV_DB_PORT=abc\n"), 0o600))
+
+		err := LoadFile(&Config{}, path, WithPrefix("LFV"))
+		assert.ErrorContains(t, err, "cannot set field LFV_DB_PORT value")
+	})
+}
@@ -0,0 +1,79 @@
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupSource(t *testing.T) {
+	t.Parallel()
+
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		Name string
+		Tags []string
+		DB   DB
+	}
+
+	lookup := func(values map[string]string) func(key string) (string, bool) {
+		return func(key string) (string, bool) {
+			v, ok := values[key]
+			return v, ok
+		}
+	}
+
+	t.Run("map", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg Config
+
+		err := New(WithPrefix("LS"), WithLookupSource(lookup(map[string]string{
+			"LS_NAME":    "app",
+			"LS_TAGS":    "a,b",
+			"LS_DB_HOST": "db.local",
+			"LS_DB_PORT": "5432",
+		}))).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, Config{Name: "app", Tags: []string{"a", "b"}, DB: DB{Host: "db.local", Port: 5432}}, cfg)
+	})
+
+	t.Run("environment is not read", func(t *testing.T) {
+		t.Parallel()
+
+		// PATH is set in the process environment
+		var cfg struct {
+			Path string
+			Home string
+		}
+
+		err := New(WithLookupSource(lookup(map[string]string{"HOME": "/home/app"}))).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Path)
+		assert.Equal(t, "/home/app", cfg.Home)
+	})
+
+	t.Run("with kv source", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg Config
+
+		src := memorySource{values: map[string]string{"LSKV_NAME": "from-kv", "LSKV_DB_HOST": "kv.local"}}
+		err := New(
+			WithPrefix("LSKV"),
+			WithLookupSource(lookup(map[string]string{"LSKV_NAME": "from-lookup"})),
+			WithKVSource(src, PreferEnv),
+		).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, "from-lookup", cfg.Name)
+		assert.Equal(t, "kv.local", cfg.DB.Host)
+	})
+}
@@ -0,0 +1,57 @@
+package goconfig
+
+import "reflect"
+
+// collectionToMerge returns the current value of a slice or map field that
+// holds elements when WithMergeCollections is set, and an invalid value otherwise.
+func (c *Loader) collectionToMerge(vf reflect.Value) reflect.Value {
+	if !c.mergeCollections || (vf.Kind() != reflect.Slice && vf.Kind() != reflect.Map) || vf.Len() == 0 {
+		return reflect.Value{}
+	}
+
+	// slices and maps are references, so the copy sees the old elements even
+	// after the field is set to the loaded value
+	prev := reflect.New(vf.Type()).Elem()
+	prev.Set(vf)
+
+	return prev
+}
+
+// mergeCollection combines the loaded value of vf with prev: loaded slice
+// elements are appended to the previous ones, and loaded map entries are added
+// to the previous entries, replacing those with the same key. The result is a
+// new slice or map, so values shared with the defaults are left untouched.
+func (*Loader) mergeCollection(vf, prev reflect.Value) {
+	if !prev.IsValid() {
+		return
+	}
+
+	switch vf.Kind() {
+	case reflect.Slice:
+		merged := reflect.MakeSlice(vf.Type(), 0, prev.Len()+vf.Len())
+		merged = reflect.AppendSlice(merged, prev)
+		vf.Set(reflect.AppendSlice(merged, vf))
+	case reflect.Map:
+		merged := reflect.MakeMapWithSize(vf.Type(), prev.Len()+vf.Len())
+
+		for _, m := range []reflect.Value{prev, vf} {
+			iter := m.MapRange()
+			for iter.Next() {
+				merged.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+
+		vf.Set(merged)
+	}
+}
+
+// initEmptyCollection sets a nil map or slice field to an empty one when
+// WithInitEmptyMaps or WithInitEmptySlices is set. Pointers are left nil.
+func (c *Loader) initEmptyCollection(vf reflect.Value) {
+	switch {
+	case vf.Kind() == reflect.Map && c.initEmptyMaps && vf.IsNil():
+		vf.Set(reflect.MakeMap(vf.Type()))
+	case vf.Kind() == reflect.Slice && c.initEmptySlices && vf.IsNil():
+		vf.Set(reflect.MakeSlice(vf.Type(), 0, 0))
+	}
+}
@@ -0,0 +1,135 @@
+package goconfig
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeCollections(t *testing.T) {
+	type Config struct {
+		Hosts  []string
+		Labels map[string]string `format:"kv"`
+		Limits map[string]int
+		Ports  []int
+		Name   string
+	}
+
+	loader := New(WithPrefix("MC"), WithMergeCollections())
+
+	t.Setenv("MC_HOSTS", "a,b")
+	t.Setenv("MC_LABELS", "team=core,env=dev")
+	t.Setenv("MC_LIMITS", `{"read":10}`)
+	t.Setenv("MC_PORTS", "80")
+	t.Setenv("MC_NAME", "first")
+
+	var cfg Config
+	require.NoError(t, loader.Load(&cfg))
+
+	t.Setenv("MC_HOSTS", "c")
+	t.Setenv("MC_LABELS", "env=prod,tier=web")
+	t.Setenv("MC_LIMITS", `{"write":5}`)
+	t.Setenv("MC_NAME", "second")
+	require.NoError(t, os.Unsetenv("MC_PORTS"))
+
+	require.NoError(t, loader.Load(&cfg))
+
+	assert.Equal(t, Config{
+		Hosts:  []string{"a", "b", "c"},
+		Labels: map[string]string{"team": "core", "env": "prod", "tier": "web"},
+		Limits: map[string]int{"read": 10, "write": 5},
+		Ports:  []int{80},
+		Name:   "second",
+	}, cfg)
+
+	t.Run("replaced by default", func(t *testing.T) {
+		cfg := Config{Hosts: []string{"a"}, Labels: map[string]string{"team": "core"}}
+		require.NoError(t, New(WithPrefix("MC")).Load(&cfg))
+
+		assert.Equal(t, []string{"c"}, cfg.Hosts)
+		assert.Equal(t, map[string]string{"env": "prod", "tier": "web"}, cfg.Labels)
+	})
+
+	t.Run("previous values are not modified", func(t *testing.T) {
+		hosts := make([]string, 1, 10)
+		hosts[0] = "a"
+		labels := map[string]string{"team": "core"}
+
+		cfg := Config{Hosts: hosts, Labels: labels}
+		require.NoError(t, loader.Load(&cfg))
+
+		assert.Equal(t, []string{"a", "c"}, cfg.Hosts)
+		assert.Equal(t, []string{"a"}, hosts)
+		assert.Equal(t, map[string]string{"team": "core"}, labels)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg := Config{Hosts: []string{"ignored"}}
+		err := New(WithPrefix("MC"), WithMergeCollections(), WithDefaults(Config{Hosts: []string{"default"}})).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"default", "c"}, cfg.Hosts)
+	})
+
+	t.Run("checks apply to the merged value", func(t *testing.T) {
+		type Limited struct {
+			Hosts []string `maxlen:"2"`
+		}
+
+		cfg := Limited{Hosts: []string{"a", "b"}}
+		assert.ErrorContains(t, loader.Load(&cfg), "MC_HOSTS")
+	})
+}
+
+func TestInitEmptyCollections(t *testing.T) {
+	type Nested struct {
+		Labels map[string]string
+	}
+
+	type Config struct {
+		Labels   map[string]string
+		Ports    map[string]int `format:"kv"`
+		Hosts    []string
+		Defaults []string
+		Set      []int
+		MapPtr   *map[string]string
+		Nested   Nested
+	}
+
+	t.Setenv("IEC_SET", "1,2")
+
+	t.Run("nil by default", func(t *testing.T) {
+		var cfg Config
+		require.NoError(t, New(WithPrefix("IEC")).Load(&cfg))
+
+		assert.Nil(t, cfg.Labels)
+		assert.Nil(t, cfg.Hosts)
+	})
+
+	t.Run("maps", func(t *testing.T) {
+		var cfg Config
+		require.NoError(t, New(WithPrefix("IEC"), WithInitEmptyMaps()).Load(&cfg))
+
+		assert.NotNil(t, cfg.Labels)
+		assert.Empty(t, cfg.Labels)
+		assert.NotNil(t, cfg.Ports)
+		assert.NotNil(t, cfg.Nested.Labels)
+		assert.Nil(t, cfg.MapPtr)
+		assert.Nil(t, cfg.Hosts)
+
+		cfg.Labels["team"] = "core"
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		cfg := Config{Defaults: []string{"a"}}
+		require.NoError(t, New(WithPrefix("IEC"), WithInitEmptySlices()).Load(&cfg))
+
+		assert.NotNil(t, cfg.Hosts)
+		assert.Empty(t, cfg.Hosts)
+		assert.Equal(t, []string{"a"}, cfg.Defaults)
+		assert.Equal(t, []int{1, 2}, cfg.Set)
+		assert.Nil(t, cfg.Labels)
+	})
+}
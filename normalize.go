@@ -0,0 +1,41 @@
+package goconfig
+
+import (
+	"os"
+	"strings"
+)
+
+// StripUnderscoreLower is an env key normalizer for WithEnvKeyNormalizer that
+// removes underscores and lowercases the key, so DB_HOST, db_host and dbhost
+// are all the same key.
+func StripUnderscoreLower(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+// envIndex returns the environment keyed by normalized env keys. It is built
+// on first use and kept for the rest of the load, so the normalizer runs once
+// per env var and not once per field.
+func (c *Loader) envIndex() map[string]string {
+	if c.state != nil && c.state.envIndex != nil {
+		return c.state.envIndex
+	}
+
+	environ := os.Environ()
+	index := make(map[string]string, len(environ))
+
+	for _, kv := range environ {
+		k, v, _ := strings.Cut(kv, "=")
+
+		// the first of several spellings normalizing to the same key wins
+		nk := c.envKeyNormalizer(k)
+		if _, ok := index[nk]; !ok {
+			index[nk] = v
+		}
+	}
+
+	if c.state != nil {
+		c.state.envIndex = index
+	}
+
+	return index
+}
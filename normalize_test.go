@@ -0,0 +1,91 @@
+package goconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvKeyNormalizer(t *testing.T) {
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		DB         DB
+		ListenAddr string
+	}
+
+	spellings := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"generated", map[string]string{"NORM_DB_HOST": "db.local", "NORM_DB_PORT": "5432", "NORM_LISTEN_ADDR": ":80"}},
+		{"lowercase", map[string]string{"norm_db_host": "db.local", "norm_db_port": "5432", "norm_listen_addr": ":80"}},
+		{"no underscores", map[string]string{"normdbhost": "db.local", "NORMDBPORT": "5432", "NormListenAddr": ":80"}},
+		{"mixed", map[string]string{"Norm_DbHost": "db.local", "NORM__DB_PORT": "5432", "norm_listenaddr": ":80"}},
+	}
+
+	for _, tt := range spellings {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var cfg Config
+			err := New(WithPrefix("NORM"), WithEnvKeyNormalizer(StripUnderscoreLower)).Load(&cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, Config{DB: DB{Host: "db.local", Port: 5432}, ListenAddr: ":80"}, cfg)
+		})
+	}
+
+	t.Run("exact key wins", func(t *testing.T) {
+		t.Setenv("NORM_LISTEN_ADDR", ":80")
+		t.Setenv("normlistenaddr", ":8080")
+
+		var cfg Config
+		err := New(WithPrefix("NORM"), WithEnvKeyNormalizer(StripUnderscoreLower)).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, ":80", cfg.ListenAddr)
+	})
+
+	t.Run("custom normalizer", func(t *testing.T) {
+		t.Setenv("NORM-LISTEN-ADDR", ":80")
+
+		var cfg Config
+		err := New(WithPrefix("NORM"), WithEnvKeyNormalizer(func(key string) string {
+			return strings.ReplaceAll(key, "-", "_")
+		})).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, ":80", cfg.ListenAddr)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Setenv("normlistenaddr", ":80")
+
+		var cfg Config
+		require.NoError(t, New(WithPrefix("NORM")).Load(&cfg))
+		assert.Empty(t, cfg.ListenAddr)
+	})
+
+	t.Run("normalizer runs once per env var", func(t *testing.T) {
+		t.Setenv("normlistenaddr", ":80")
+
+		calls := map[string]int{}
+		normalize := func(key string) string {
+			calls[key]++
+			return StripUnderscoreLower(key)
+		}
+
+		var cfg Config
+		require.NoError(t, New(WithPrefix("NORM"), WithEnvKeyNormalizer(normalize)).Load(&cfg))
+		assert.Equal(t, ":80", cfg.ListenAddr)
+		assert.Equal(t, 1, calls["normlistenaddr"])
+	})
+}
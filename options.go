@@ -1,5 +1,12 @@
 package goconfig
 
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
 // Option is a function type that modifies a Loader's configuration.
 type Option func(*Loader)
 
@@ -24,6 +31,8 @@ func WithSeparator(sep string) Option {
 // WithArraySeparator sets the separator used for array values in environment variables.
 // The default separator is ",". For example, with separator ";" and array field "Numbers",
 // the environment variable would be "1;2;3;4".
+// A whitespace separator such as " " splits around any run of spaces, tabs and
+// newlines, so ARGS="--a  --b\t--c" loads as ["--a", "--b", "--c"] with no empty elements.
 func WithArraySeparator(sep string) Option {
 	return func(c *Loader) {
 		c.arraySep = sep
@@ -38,3 +47,397 @@ func WithKeyTransformer(transformer func(key string) string) Option {
 		c.fieldNameTransformer = transformer
 	}
 }
+
+// WithOnMissing sets a callback invoked for every field whose environment variable is not set.
+// The callback receives the Go field path (e.g. "DB.Host") and the env key that was looked up.
+// Nested structs are not reported themselves, only their fields are.
+// The callback fires whenever the variable is absent, even if the field already holds a value.
+func WithOnMissing(fn func(fieldPath, envKey string)) Option {
+	return func(c *Loader) {
+		c.onMissing = fn
+	}
+}
+
+// WithKeyRename registers legacy env keys to honor during a migration.
+// The map goes from the old key to the new key, both being full env names
+// (prefix, separator, transformer and tags already applied). When the new key
+// is not set, the Loader falls back to its old keys; the new key always wins
+// when both are set. Old keys mapping to the same new key are tried in
+// lexical order. It can be used several times to add more renames.
+func WithKeyRename(renames map[string]string) Option {
+	return func(c *Loader) {
+		if c.legacyKeys == nil {
+			c.legacyKeys = map[string][]string{}
+		}
+
+		for oldKey, newKey := range renames {
+			c.legacyKeys[newKey] = append(c.legacyKeys[newKey], oldKey)
+			sort.Strings(c.legacyKeys[newKey])
+		}
+	}
+}
+
+// WithTypeParser registers a parse function for values of type t.
+// It takes precedence over the built-in parsing and over encoding.TextUnmarshaler,
+// which makes it useful for types that only offer a SetString style API.
+// The returned value must be assignable to t. Pointer fields to t are allocated as usual.
+func WithTypeParser(t reflect.Type, parse func(raw string) (any, error)) Option {
+	return func(c *Loader) {
+		if c.parsers == nil {
+			c.parsers = map[reflect.Type]func(raw string) (any, error){}
+		}
+
+		c.parsers[t] = parse
+	}
+}
+
+// WithSliceSuffixes enables indexed slices: when a slice field's own key is not set,
+// its elements are read from the keys KEY<sep><suffix> for each suffix of the sequence,
+// e.g. SERVERS_0, SERVERS_1 with NumericSuffixes or SERVERS_A, SERVERS_B with AlphaSuffixes.
+// Struct elements are read field by field, e.g. SERVERS_0_HOST.
+// Discovery stops at the first element that has no value or when the sequence ends.
+func WithSliceSuffixes(seq SuffixSequence) Option {
+	return func(c *Loader) {
+		c.sliceSuffixes = seq
+	}
+}
+
+// WithDefaults seeds the loaded struct with a deep copy of prototype before
+// reading the environment, so only fields present in the environment override it.
+// The prototype may be a struct or a pointer to a struct of the same type as the
+// loaded struct; Load returns an error when the types differ.
+func WithDefaults(prototype any) Option {
+	return func(c *Loader) {
+		c.defaults = prototype
+	}
+}
+
+// WithValueTransformer sets a function applied to every raw value read from the
+// environment before it is parsed. It receives the env key and the raw value and
+// returns the value to parse, e.g. to trim a known prefix or decode an obfuscation.
+func WithValueTransformer(transformer func(key, raw string) string) Option {
+	return func(c *Loader) {
+		c.valueTransformer = transformer
+	}
+}
+
+// WithPrefixCaseFold makes the prefix match case-insensitively, so APP_HOST,
+// app_HOST and App_HOST are all found for prefix "APP" and field Host.
+// Only the prefix is folded; the rest of the key is matched as configured.
+// A variable spelled exactly like the generated key always wins.
+func WithPrefixCaseFold() Option {
+	return func(c *Loader) {
+		c.prefixCaseFold = true
+	}
+}
+
+// WithSliceTrimEmpty controls whether empty elements of a separated slice value
+// are dropped. By default they are preserved for backward compatibility, so
+// "a,,b" loads as ["a", "", "b"]; with trim enabled it loads as ["a", "b"], which
+// is usually what is wanted for lists edited by hand. Elements made of spaces
+// are not empty.
+func WithSliceTrimEmpty(trim bool) Option {
+	return func(c *Loader) {
+		c.sliceTrimEmpty = trim
+	}
+}
+
+// WithEnum registers the names of an integer enum type t, e.g. a `type Env int`
+// with constants. Values of t are parsed from their name, and unknown names
+// produce an error listing the valid ones. Names are matched exactly.
+// It is a shorthand for WithTypeParser and replaces any parser registered for t.
+func WithEnum(t reflect.Type, names map[string]int64) Option {
+	return WithTypeParser(t, enumParser(t, names))
+}
+
+// WithKVSource makes the Loader resolve keys from src in addition to, or instead
+// of, the environment. The precedence decides which one wins when both have a key:
+// PreferEnv, PreferKVSource or KVSourceOnly. An error returned by src stops Load.
+func WithKVSource(src KVSource, precedence Precedence) Option {
+	return func(c *Loader) {
+		c.kvSource = src
+		c.kvPrecedence = precedence
+	}
+}
+
+// WithBoolLiterals registers additional words for true and false, e.g. "yes"/"no"
+// or "on"/"off". They are matched case-insensitively, so "Yes" and "YES" work too.
+// The forms accepted by strconv.ParseBool ("1", "t", "T", "TRUE", "true", "True",
+// "0", "f", "F", "FALSE", "false", "False") keep working and keep their exact casing.
+// It can be used several times to add more literals.
+func WithBoolLiterals(trueLiterals, falseLiterals []string) Option {
+	return func(c *Loader) {
+		if c.boolLiterals == nil {
+			c.boolLiterals = map[string]bool{}
+		}
+
+		for _, l := range trueLiterals {
+			c.boolLiterals[strings.ToLower(l)] = true
+		}
+
+		for _, l := range falseLiterals {
+			c.boolLiterals[strings.ToLower(l)] = false
+		}
+	}
+}
+
+// WithFailFastOnUnsupportedType makes Load walk the struct type before reading
+// the environment and return ErrUnsupportedType errors for every reachable field
+// whose type cannot be loaded, even when its variable is not set. Without it, such
+// a field only fails when its variable is present. Other problems reported by
+// Check are ignored.
+func WithFailFastOnUnsupportedType() Option {
+	return func(c *Loader) {
+		c.failFast = true
+	}
+}
+
+// WithRequiredGroups makes Load check the fields tagged with group:"name":
+// the fields of a group must be either all set or all unset. A partially set
+// group returns an ErrPartialGroup error naming the group and its missing keys.
+// A field can belong to several groups, e.g. group:"db,tls".
+func WithRequiredGroups() Option {
+	return func(c *Loader) {
+		c.requiredGroups = true
+	}
+}
+
+// WithOverrideFromArgs reads KEY=VALUE overrides from command line arguments,
+// usually os.Args[1:]. Only tokens following the marker are used, either as
+// "--set KEY=VALUE" or "--set=KEY=VALUE" with the DefaultArgsMarker, so other
+// arguments are never consumed; an empty marker means DefaultArgsMarker. Keys
+// are full env keys (e.g. APP_PORT) and overrides take precedence over the
+// environment and the KVSource. Tokens without "=" are ignored. When a key is
+// given several times, the last one wins.
+func WithOverrideFromArgs(args []string, marker string) Option {
+	return func(c *Loader) {
+		if marker == "" {
+			marker = DefaultArgsMarker
+		}
+
+		if c.argOverrides == nil {
+			c.argOverrides = map[string]string{}
+		}
+
+		for k, v := range parseArgOverrides(args, marker) {
+			c.argOverrides[k] = v
+		}
+	}
+}
+
+// WithStructInit sets a hook asked for an initialized instance every time the
+// Loader is about to load the fields of a nested struct, for types that are not
+// usable in their zero state (e.g. with maps or unexported defaults to set up).
+// It receives the struct type and returns a value or a pointer of that type and
+// true; returning false keeps the zero value. Struct fields holding a non-zero
+// value are left as they are, and nil pointers are only set when one of the
+// struct's variables is found, as without the hook.
+func WithStructInit(init func(t reflect.Type) (any, bool)) Option {
+	return func(c *Loader) {
+		c.structInit = init
+	}
+}
+
+// WithEnvKeyNormalizer sets a function applied to both the generated key and the
+// keys of the environment before they are compared, for keys that are not set
+// exactly as generated. With StripUnderscoreLower, field DBHost under prefix APP
+// is found as APP_DB_HOST, app_dbhost or APPDBHOST.
+// The environment is indexed by normalized key once per Load, so the normalizer
+// runs once per env var rather than once per field. A variable spelled exactly
+// like the generated key always wins.
+func WithEnvKeyNormalizer(normalize func(key string) string) Option {
+	return func(c *Loader) {
+		c.envKeyNormalizer = normalize
+	}
+}
+
+// WithMergeCollections makes loading into a struct that already holds values
+// extend its slices and maps instead of replacing them, to assemble a
+// configuration over several loads. A slice field with elements gets the loaded
+// elements appended; a map field with entries gets the loaded entries added, a
+// loaded entry replacing the one with the same key. Length and oneof checks
+// apply to the merged value. Collections whose variable is not set are left as
+// they are, as without the option. With WithDefaults the struct is reset to the
+// defaults first, so collections merge into the defaults' contents instead.
+func WithMergeCollections() Option {
+	return func(c *Loader) {
+		c.mergeCollections = true
+	}
+}
+
+// WithStdin sets the reader used for fields tagged stdin:"true" whose variable
+// is set to StdinSentinel ("-"), so CLI tools can prompt for secrets instead of
+// keeping them in the environment or shell history. Each such field reads one
+// line, in field order. It defaults to os.Stdin and mainly exists for tests.
+func WithStdin(r io.Reader) Option {
+	return func(c *Loader) {
+		c.stdin = r
+	}
+}
+
+// WithKeyDepthLimit limits how deep the Loader generates keys for nested
+// structs: a field of the loaded struct is at depth 1, a field of one of its
+// struct fields at depth 2, and so on, while embedded and squashed structs add
+// no depth. A struct whose fields would be deeper than n is treated like a field
+// tagged leaf:"true" and read from its own variable as JSON, which keeps large
+// or third-party types from multiplying the keys looked up. It only controls
+// which keys exist; it is not a guard against recursive types. Zero or less
+// means no limit, the default.
+func WithKeyDepthLimit(n int) Option {
+	return func(c *Loader) {
+		c.keyDepthLimit = n
+	}
+}
+
+// WithFieldAccessor registers a setter for values of type t, for encapsulated
+// types that hide their state behind methods instead of exported fields or
+// encoding.TextUnmarshaler. set must be a func(*T, V) or func(*T, V) error where
+// T is t; the Loader parses the raw value into V like a field of type V and calls
+// set with a pointer to the value being loaded, e.g.
+//
+//	WithFieldAccessor(reflect.TypeOf(Port{}), func(p *Port, v uint16) error { return p.Set(v) })
+//
+// A setter takes precedence over encoding.TextUnmarshaler, but not over a parser
+// registered with WithTypeParser. Load returns an error when set has another signature.
+func WithFieldAccessor(t reflect.Type, set any) Option {
+	return func(c *Loader) {
+		if c.accessors == nil {
+			c.accessors = map[reflect.Type]reflect.Value{}
+		}
+
+		c.accessors[t] = reflect.ValueOf(set)
+	}
+}
+
+// WithValueValidator registers a function called with the loaded struct (the
+// pointer given to Load) once all fields are loaded and checked, for rules that
+// span several fields, e.g. a certificate path required when TLS is enabled.
+// Validators run in the order they were registered and Load returns the first
+// error, wrapped so errors.Is and errors.As still match it.
+func WithValueValidator(validate func(s any) error) Option {
+	return func(c *Loader) {
+		c.validators = append(c.validators, validate)
+	}
+}
+
+// WithStructTagAliasSeparator makes alias tags lists of names separated by sep,
+// tried in order, e.g. alias:"DB_HOST,DATABASE_HOST" with ",". Every name is
+// combined with the prefix and the parent keys like a single alias, so with
+// prefix APP the field above reads APP_DB_HOST, then APP_DATABASE_HOST. The
+// first name is the field's key: it names the keys of nested struct fields and
+// is reported in errors unless another name matched.
+func WithStructTagAliasSeparator(sep string) Option {
+	return func(c *Loader) {
+		c.aliasSep = sep
+	}
+}
+
+// WithDeprecationHandler sets the function called when a field tagged
+// deprecated:"message" gets a value, with the env key that supplied it and the
+// tag's message, e.g. deprecated:"use APP_DB_URL instead". Fields whose key is
+// unset are not reported. By default the warning is written with log.Printf;
+// pass a function that does nothing to silence it.
+func WithDeprecationHandler(handler func(key, message string)) Option {
+	return func(c *Loader) {
+		c.deprecationHandler = handler
+	}
+}
+
+// WithErrorAggregation makes Load keep going when a field fails to load and
+// return every failure at once, each naming its env key, e.g. to see all
+// misconfigured variables of a CI run. The returned error joins ErrLoad with the
+// collected errors, so errors.Is matches ErrLoad as well as the sentinel of each
+// failure, e.g. ErrMissingRequired. Partial groups and every failing validator
+// registered with WithValueValidator are collected too.
+func WithErrorAggregation() Option {
+	return func(c *Loader) {
+		c.aggregateErrors = true
+	}
+}
+
+// WithKVBareKeys makes a key without "=" in a map tagged format:"kv" load with
+// an empty value, like "key=": FLAGS=verbose,level=2 loads as
+// {"verbose": "", "level": "2"}. Without it, a bare key is an invalid pair.
+func WithKVBareKeys() Option {
+	return func(c *Loader) {
+		c.kvBareKeys = true
+	}
+}
+
+// WithTimeLayout sets the layout time.Time fields are parsed with, e.g.
+// time.DateTime or "2006-01-02". The default is time.RFC3339. A field can
+// override it with a layout tag, e.g. `layout:"2006-01-02"`.
+func WithTimeLayout(layout string) Option {
+	return func(c *Loader) {
+		c.timeLayout = layout
+	}
+}
+
+// WithGlobalDefault registers fn to compute the default of fields whose
+// variable is not set and that hold no default from WithDefaults or the loaded
+// struct. fn receives the dotted path of the field, e.g. "DB.URL", and returns
+// the value to parse as if it came from the environment, or false for no default.
+// Nested structs are not passed to fn, their fields are.
+func WithGlobalDefault(fn func(fieldPath string) (string, bool)) Option {
+	return func(c *Loader) {
+		c.globalDefault = fn
+	}
+}
+
+// WithLookupSource makes Load read variables with lookup instead of
+// os.LookupEnv, e.g. from a map[string]string in tests, so values can be
+// injected without mutating the process environment. lookup receives the full
+// key and reports whether it exists. It is a shorthand for
+// WithSources(SourceFunc(lookup)), see WithSources for how it combines with
+// other sources.
+func WithLookupSource(lookup func(key string) (string, bool)) Option {
+	return WithSources(SourceFunc(lookup))
+}
+
+// WithStrictPrefix makes Load return an ErrUnknownVariable error for every
+// variable of the process environment under the prefix (e.g. APP_) that no
+// field reads, to catch typos such as APP_POTR for APP_PORT. Variables outside
+// the prefix are never reported, and it has no effect without WithPrefix.
+// Variables of fields skipped by an enabledby tag are reported as well.
+func WithStrictPrefix() Option {
+	return func(c *Loader) {
+		c.strictPrefix = true
+	}
+}
+
+// WithInitEmptyMaps makes Load set map fields whose variable is not set and
+// that hold no default to an empty map instead of leaving them nil, so they
+// can be written to. Pointers to maps are left nil.
+func WithInitEmptyMaps() Option {
+	return func(c *Loader) {
+		c.initEmptyMaps = true
+	}
+}
+
+// WithInitEmptySlices is the slice analog of WithInitEmptyMaps: unset slice
+// fields become empty, non-nil slices, e.g. to encode as [] rather than null.
+func WithInitEmptySlices() Option {
+	return func(c *Loader) {
+		c.initEmptySlices = true
+	}
+}
+
+// WithSources makes Load resolve every key by consulting sources in order,
+// the first one holding the key winning, e.g. the environment, then a dotenv
+// file, then defaults:
+//
+//	WithSources(goconfig.EnvSource{}, file, goconfig.MapSource(defaults))
+//
+// The sources take the place of the process environment, which is read only
+// when EnvSource is one of them. Several WithSources and WithLookupSource
+// options add their sources to the list in the order the options are given.
+// Values of WithOverrideFromArgs come before the list, and a WithKVSource
+// before or after it depending on its precedence; with KVSourceOnly the list
+// is not consulted.
+// WithPrefixCaseFold and WithEnvKeyNormalizer only apply to EnvSource.
+// WithSources without sources does nothing, so the environment is still read.
+func WithSources(sources ...Source) Option {
+	return func(c *Loader) {
+		c.sources = append(c.sources, sources...)
+	}
+}
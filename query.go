@@ -0,0 +1,81 @@
+package goconfig
+
+import (
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// setQueryVal parses a URL query string such as "host=localhost&port=5432" into
+// the fields of a struct. A key matches a field by its name, or by the name given
+// by the field name transformer (e.g. ssl_mode for SSLMode), ignoring case.
+// Repeated keys fill a slice field with one element each; for other fields the
+// last value wins. Keys that match no field are an error.
+func (c *Loader) setQueryVal(vf reflect.Value, raw string) error {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return errors.Wrap(err, "invalid query string")
+	}
+
+	t := c.getDirectType(vf.Type())
+	s := reflect.New(t).Elem()
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		i, ok := c.queryField(t, key)
+		if !ok {
+			return errors.Errorf("unknown query key %q", key)
+		}
+
+		if err := c.setQueryField(s.Field(i), t.Field(i), values[key]); err != nil {
+			return errors.Wrapf(err, "cannot set query key %q", key)
+		}
+	}
+
+	if vf.Kind() == reflect.Pointer {
+		s = s.Addr()
+	}
+
+	vf.Set(s)
+
+	return nil
+}
+
+// queryField returns the index of the exported field of t matching the query key.
+func (c *Loader) queryField(t reflect.Type, key string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		tf := t.Field(i)
+		if !tf.IsExported() {
+			continue
+		}
+
+		if strings.EqualFold(key, tf.Name) ||
+			c.fieldNameTransformer != nil && strings.EqualFold(key, c.fieldNameTransformer(tf.Name)) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func (c *Loader) setQueryField(fv reflect.Value, tf reflect.StructField, values []string) error {
+	if tf.Type.Kind() != reflect.Slice || len(values) == 1 {
+		_, err := c.setFieldVal(fv, values[len(values)-1], tf.Tag)
+		return err
+	}
+
+	slice := reflect.MakeSlice(tf.Type, len(values), len(values))
+
+	for i, v := range values {
+		if _, err := c.setFieldVal(slice.Index(i), v, ""); err != nil {
+			return errors.Wrapf(err, "cannot set slice value")
+		}
+	}
+
+	fv.Set(slice)
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package goconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFormat(t *testing.T) {
+	type DSN struct {
+		Host    string
+		Port    int
+		SSL     bool
+		SSLMode string
+		Timeout time.Duration
+		Hosts   []string
+		Weights []int
+	}
+
+	type Config struct {
+		DB      DSN  `format:"query"`
+		Replica *DSN `format:"query"`
+	}
+
+	load := func(t *testing.T, env map[string]string) (Config, error) {
+		t.Helper()
+
+		for k, v := range env {
+			t.Setenv(k, v)
+		}
+
+		var cfg Config
+		err := New(WithPrefix("QS")).Load(&cfg)
+
+		return cfg, err
+	}
+
+	t.Run("scalars", func(t *testing.T) {
+		cfg, err := load(t, map[string]string{
+			"QS_DB":      "host=localhost&port=5432&ssl=true&ssl_mode=verify-full&timeout=5s",
+			"QS_REPLICA": "HOST=replica&Port=5433",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, DSN{Host: "localhost", Port: 5432, SSL: true, SSLMode: "verify-full", Timeout: 5 * time.Second}, cfg.DB)
+		assert.Equal(t, &DSN{Host: "replica", Port: 5433}, cfg.Replica)
+	})
+
+	t.Run("repeated keys", func(t *testing.T) {
+		cfg, err := load(t, map[string]string{
+			"QS_DB": "hosts=a&hosts=b%2Cc&weights=1,2&port=1&port=2",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b,c"}, cfg.DB.Hosts, "repeated keys are one element each")
+		assert.Equal(t, []int{1, 2}, cfg.DB.Weights, "a single value is split like any slice")
+		assert.Equal(t, 2, cfg.DB.Port, "the last value wins")
+	})
+
+	t.Run("fields are not read one by one", func(t *testing.T) {
+		cfg, err := load(t, map[string]string{"QS_DB_HOST": "ignored"})
+
+		require.NoError(t, err)
+		assert.Equal(t, DSN{}, cfg.DB)
+		assert.Nil(t, cfg.Replica)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := load(t, map[string]string{"QS_DB": "host=localhost&user=app"})
+		assert.ErrorContains(t, err, `cannot set field QS_DB value: unknown query key "user"`)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := load(t, map[string]string{"QS_DB": "port=http"})
+		assert.ErrorContains(t, err, `cannot set query key "port"`)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := load(t, map[string]string{"QS_DB": "host=%zz"})
+		assert.ErrorContains(t, err, "invalid query string")
+	})
+}
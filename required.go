@@ -0,0 +1,27 @@
+package goconfig
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMissingRequired is returned by Load for a field tagged required:"true"
+// whose variable is not set and that holds no default value.
+var ErrMissingRequired = errors.New("required variable is not set")
+
+// checkRequired returns an ErrMissingRequired error naming envKey when the
+// field is tagged required:"true" and still holds its zero value, i.e. no
+// default was given with WithDefaults or in the loaded struct.
+func (*Loader) checkRequired(tf reflect.StructField, vf reflect.Value, envKey string) error {
+	if required, _ := strconv.ParseBool(tf.Tag.Get("required")); !required {
+		return nil
+	}
+
+	if !vf.IsZero() {
+		return nil
+	}
+
+	return errors.Wrap(ErrMissingRequired, envKey)
+}
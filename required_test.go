@@ -0,0 +1,89 @@
+package goconfig
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredTag(t *testing.T) {
+	type Credentials struct {
+		User string `required:"true"`
+	}
+
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		Credentials
+		Token   string `env:"RQ_TOKEN_EXACT" required:"true"`
+		Host    string `required:"true"`
+		Port    int    `required:"true"`
+		DB      DB     `required:"true"`
+		Replica *DB    `required:"false"`
+		Debug   bool   `required:"false"`
+	}
+
+	setAll := func(t *testing.T) {
+		t.Setenv("RQ_TOKEN_EXACT", "secret")
+		t.Setenv("RQ_USER", "admin")
+		t.Setenv("RQ_HOST", "localhost")
+		t.Setenv("RQ_PORT", "0")
+		t.Setenv("RQ_DB_HOST", "db")
+	}
+
+	t.Run("all set", func(t *testing.T) {
+		setAll(t)
+
+		var cfg Config
+		require.NoError(t, New(WithPrefix("RQ")).Load(&cfg))
+		assert.Zero(t, cfg.Port, "a set variable satisfies required even with a zero value")
+	})
+
+	tests := []struct {
+		unset string
+		key   string
+	}{
+		{"RQ_TOKEN_EXACT", "RQ_TOKEN_EXACT"},
+		{"RQ_HOST", "RQ_HOST"},
+		{"RQ_PORT", "RQ_PORT"},
+		{"RQ_USER", "RQ_USER"},
+		{"RQ_DB_HOST", "RQ_DB"},
+	}
+
+	for _, tt := range tests {
+		t.Run("missing "+tt.unset, func(t *testing.T) {
+			setAll(t)
+			t.Setenv(tt.unset, "")
+			require.NoError(t, os.Unsetenv(tt.unset))
+
+			err := New(WithPrefix("RQ")).Load(&Config{})
+			assert.ErrorIs(t, err, ErrMissingRequired)
+			assert.EqualError(t, err, tt.key+": required variable is not set")
+		})
+	}
+
+	t.Run("defaults satisfy required", func(t *testing.T) {
+		setAll(t)
+		t.Setenv("RQ_HOST", "")
+		require.NoError(t, os.Unsetenv("RQ_HOST"))
+
+		var cfg Config
+		require.NoError(t, New(WithPrefix("RQ"), WithDefaults(Config{Host: "fallback"})).Load(&cfg))
+		assert.Equal(t, "fallback", cfg.Host)
+	})
+
+	t.Run("embedded structs are not required", func(t *testing.T) {
+		type Config struct {
+			Credentials `required:"true"`
+		}
+
+		t.Setenv("RQ_USER", "admin")
+
+		assert.NoError(t, New(WithPrefix("RQ")).Load(&Config{}))
+	})
+}
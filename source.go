@@ -0,0 +1,206 @@
+package goconfig
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/jkaveri/goconfig/internal/dotenv"
+	"github.com/pkg/errors"
+)
+
+// KVSource is a key-value store the Loader can read values from, e.g. a
+// Consul or etcd client. Get receives the full key (prefix, separator,
+// transformer and tags already applied) and reports whether the key exists.
+type KVSource interface {
+	Get(key string) (string, bool, error)
+}
+
+// Precedence defines the order in which the environment and the KVSource are consulted.
+type Precedence int
+
+const (
+	// PreferEnv reads the environment first and falls back to the KVSource.
+	PreferEnv Precedence = iota
+	// PreferKVSource reads the KVSource first and falls back to the environment.
+	PreferKVSource
+	// KVSourceOnly reads the KVSource and ignores the environment.
+	KVSourceOnly
+)
+
+// Source is a layer of variables consulted by WithSources, e.g. the process
+// environment, a dotenv file or a map of defaults. Lookup receives the full
+// key (prefix, separator, transformer and tags already applied) and reports
+// whether the key exists.
+type Source interface {
+	Lookup(key string) (string, bool)
+}
+
+// EnvSource is the Source of the process environment. In the sources of a
+// Loader it honors WithPrefixCaseFold and WithEnvKeyNormalizer, which only
+// apply to the process environment.
+type EnvSource struct{}
+
+// Lookup looks up key with os.LookupEnv.
+func (EnvSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// MapSource is a Source of fixed values, e.g. defaults or values in tests.
+type MapSource map[string]string
+
+// Lookup returns the value stored under key.
+func (m MapSource) Lookup(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// SourceFunc adapts a function to a Source.
+type SourceFunc func(key string) (string, bool)
+
+// Lookup calls f.
+func (f SourceFunc) Lookup(key string) (string, bool) {
+	return f(key)
+}
+
+// DotenvSource reads the dotenv file at path into a MapSource, with the same
+// syntax as LoadFile.
+func DotenvSource(path string) (MapSource, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read env file %s", path)
+	}
+
+	entries, err := dotenv.Parse(bytes.NewReader(content), dotenv.WithCommentStrip())
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse env file %s", path)
+	}
+
+	return entries, nil
+}
+
+// layer is a source consulted by lookupKey, which can fail like a KVSource.
+type layer func(key string) (string, bool, error)
+
+// layers returns the sources consulted in order for every key: the argument
+// overrides, then the sources of WithSources (the process environment by
+// default) and the KVSource in the order defined by its precedence.
+func (c *Loader) layers() []layer {
+	if c.state != nil && c.state.layers != nil {
+		return c.state.layers
+	}
+
+	layers := []layer{}
+
+	if len(c.argOverrides) > 0 {
+		layers = append(layers, c.sourceLayer(MapSource(c.argOverrides)))
+	}
+
+	srcs := c.sources
+	if srcs == nil {
+		srcs = []Source{EnvSource{}}
+	}
+
+	sources := make([]layer, 0, len(srcs))
+	for _, src := range srcs {
+		sources = append(sources, c.sourceLayer(src))
+	}
+
+	switch {
+	case c.kvSource == nil:
+		layers = append(layers, sources...)
+	case c.kvPrecedence == PreferEnv:
+		layers = append(append(layers, sources...), c.kvLayer)
+	case c.kvPrecedence == PreferKVSource:
+		layers = append(append(layers, c.kvLayer), sources...)
+	default:
+		layers = append(layers, c.kvLayer)
+	}
+
+	if c.state != nil {
+		c.state.layers = layers
+	}
+
+	return layers
+}
+
+// sourceLayer adapts src to a layer. An EnvSource is read like the
+// environment is read without sources, folding and normalizing keys.
+func (c *Loader) sourceLayer(src Source) layer {
+	if _, ok := src.(EnvSource); ok {
+		return func(key string) (string, bool, error) {
+			v, ok := c.getenv(key)
+			return v, ok, nil
+		}
+	}
+
+	return func(key string) (string, bool, error) {
+		v, ok := src.Lookup(key)
+		return v, ok, nil
+	}
+}
+
+// kvLayer looks up key in the KVSource.
+func (c *Loader) kvLayer(key string) (string, bool, error) {
+	v, ok, err := c.kvSource.Get(key)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "cannot read %s from kv source", key)
+	}
+
+	return v, ok, nil
+}
+
+// lookupKey looks up a single key in the layers of the Loader, in order.
+func (c *Loader) lookupKey(key string) (string, bool, error) {
+	c.markLookedUp(key)
+
+	for _, l := range c.layers() {
+		if v, ok, err := l(key); ok || err != nil {
+			return v, ok, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// probeKey reports whether key is set in one of the layers, like lookupKey but
+// without recording it for WithStrictPrefix. An error counts as not set, it is
+// returned when the key is looked up.
+func (c *Loader) probeKey(key string) bool {
+	for _, l := range c.layers() {
+		if _, ok, err := l(key); ok || err != nil {
+			return ok
+		}
+	}
+
+	return false
+}
+
+// DefaultArgsMarker is the marker WithOverrideFromArgs uses when none is given.
+const DefaultArgsMarker = "--set"
+
+// parseArgOverrides collects the KEY=VALUE tokens following marker in args,
+// given either as "--set KEY=VALUE" or "--set=KEY=VALUE". Other args are ignored.
+func parseArgOverrides(args []string, marker string) map[string]string {
+	overrides := map[string]string{}
+
+	for i := 0; i < len(args); i++ {
+		var token string
+
+		switch {
+		case args[i] == marker && i+1 < len(args):
+			i++
+			token = args[i]
+		case strings.HasPrefix(args[i], marker+"="):
+			token = strings.TrimPrefix(args[i], marker+"=")
+		default:
+			continue
+		}
+
+		if key, value, ok := strings.Cut(token, "="); ok && key != "" {
+			overrides[key] = value
+		}
+	}
+
+	return overrides
+}
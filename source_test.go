@@ -0,0 +1,260 @@
+package goconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memorySource is an in-memory KVSource.
+type memorySource struct {
+	values map[string]string
+	err    error
+}
+
+func (m memorySource) Get(key string) (string, bool, error) {
+	if m.err != nil {
+		return "", false, m.err
+	}
+
+	v, ok := m.values[key]
+
+	return v, ok, nil
+}
+
+func TestKVSource(t *testing.T) {
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Config struct {
+		DB      DB
+		Replica string
+	}
+
+	src := memorySource{values: map[string]string{
+		"KV_DB_HOST": "kv-host",
+		"KV_DB_PORT": "5432",
+	}}
+
+	t.Setenv("KV_DB_HOST", "env-host")
+	t.Setenv("KV_REPLICA", "env-replica")
+
+	tests := []struct {
+		name       string
+		precedence Precedence
+		expected   Config
+	}{
+		{
+			name:       "prefer env",
+			precedence: PreferEnv,
+			expected:   Config{DB: DB{Host: "env-host", Port: 5432}, Replica: "env-replica"},
+		},
+		{
+			name:       "prefer kv source",
+			precedence: PreferKVSource,
+			expected:   Config{DB: DB{Host: "kv-host", Port: 5432}, Replica: "env-replica"},
+		},
+		{
+			name:       "kv source only",
+			precedence: KVSourceOnly,
+			expected:   Config{DB: DB{Host: "kv-host", Port: 5432}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			err := New(WithPrefix("KV"), WithKVSource(src, tt.precedence)).Load(&cfg)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+
+	t.Run("source error", func(t *testing.T) {
+		failing := memorySource{err: errors.New("connection refused")}
+
+		err := New(WithPrefix("KV"), WithKVSource(failing, PreferKVSource)).Load(&Config{})
+		assert.ErrorContains(t, err, "cannot read KV_DB from kv source: connection refused")
+	})
+}
+
+func TestOverrideFromArgs(t *testing.T) {
+	type Config struct {
+		Port    int
+		Host    string
+		Debug   bool
+		Verbose bool
+	}
+
+	t.Setenv("ARGS_PORT", "8080")
+	t.Setenv("ARGS_HOST", "env-host")
+
+	args := []string{
+		"serve",
+		"--set", "ARGS_PORT=9090",
+		"--set=ARGS_DEBUG=true",
+		"ARGS_VERBOSE=true",
+		"--set", "not-a-pair",
+		"--set=ARGS_PORT=9191",
+	}
+
+	t.Run("default marker", func(t *testing.T) {
+		var cfg Config
+		err := New(WithPrefix("ARGS"), WithOverrideFromArgs(args, "")).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 9191, Host: "env-host", Debug: true}, cfg)
+	})
+
+	t.Run("custom marker", func(t *testing.T) {
+		var cfg Config
+		err := New(WithPrefix("ARGS"), WithOverrideFromArgs([]string{"-o", "ARGS_HOST=arg-host", "--set=ARGS_DEBUG=true"}, "-o")).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 8080, Host: "arg-host"}, cfg)
+	})
+
+	t.Run("args win over kv source", func(t *testing.T) {
+		src := memorySource{values: map[string]string{"ARGS_PORT": "7070"}}
+
+		var cfg Config
+		err := New(
+			WithPrefix("ARGS"),
+			WithKVSource(src, KVSourceOnly),
+			WithOverrideFromArgs([]string{"--set", "ARGS_PORT=6060"}, ""),
+		).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6060, cfg.Port)
+	})
+}
+
+func TestSources(t *testing.T) {
+	type Config struct {
+		Port  int
+		Host  string
+		Debug bool
+		Name  string
+	}
+
+	flags := MapSource{"SOURCES_PORT": "9090"}
+	t.Setenv("SOURCES_PORT", "8080")
+	t.Setenv("SOURCES_HOST", "env-host")
+	defaults := MapSource{
+		"SOURCES_PORT":  "80",
+		"SOURCES_HOST":  "localhost",
+		"SOURCES_DEBUG": "true",
+	}
+
+	t.Run("first source wins", func(t *testing.T) {
+		var cfg Config
+		err := New(WithPrefix("SOURCES"), WithSources(flags, EnvSource{}, defaults)).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 9090, Host: "env-host", Debug: true}, cfg)
+	})
+
+	t.Run("order decides", func(t *testing.T) {
+		var cfg Config
+		err := New(WithPrefix("SOURCES"), WithSources(defaults, EnvSource{}, flags)).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 80, Host: "localhost", Debug: true}, cfg)
+	})
+
+	t.Run("source func", func(t *testing.T) {
+		var keys []string
+		name := SourceFunc(func(key string) (string, bool) {
+			keys = append(keys, key)
+			return "from-func", key == "SOURCES_NAME"
+		})
+
+		var cfg Config
+		err := New(WithPrefix("SOURCES"), WithSources(name, defaults)).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 80, Host: "localhost", Debug: true, Name: "from-func"}, cfg)
+		assert.Contains(t, keys, "SOURCES_PORT")
+	})
+
+	t.Run("options add up", func(t *testing.T) {
+		var cfg Config
+		err := New(
+			WithPrefix("SOURCES"),
+			WithLookupSource(flags.Lookup),
+			WithSources(EnvSource{}),
+			WithSources(defaults),
+		).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 9090, Host: "env-host", Debug: true}, cfg)
+	})
+
+	t.Run("env source folds and normalizes keys", func(t *testing.T) {
+		t.Setenv("sources_NAME", "folded")
+		t.Setenv("SOURCES__DEBUG", "false")
+
+		var cfg Config
+		err := New(
+			WithPrefix("SOURCES"),
+			WithPrefixCaseFold(),
+			WithEnvKeyNormalizer(func(key string) string { return strings.ReplaceAll(key, "__", "_") }),
+			WithSources(EnvSource{}, defaults),
+		).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 8080, Host: "env-host", Debug: false, Name: "folded"}, cfg)
+	})
+
+	t.Run("args and kv source around sources", func(t *testing.T) {
+		kv := memorySource{values: map[string]string{"SOURCES_PORT": "7070", "SOURCES_NAME": "from-kv"}}
+		args := []string{"--set", "SOURCES_HOST=arg-host"}
+
+		var cfg Config
+		err := New(
+			WithPrefix("SOURCES"),
+			WithSources(flags, defaults),
+			WithKVSource(kv, PreferKVSource),
+			WithOverrideFromArgs(args, ""),
+		).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 7070, Host: "arg-host", Debug: true, Name: "from-kv"}, cfg)
+
+		cfg = Config{}
+		err = New(
+			WithPrefix("SOURCES"),
+			WithSources(flags, defaults),
+			WithKVSource(kv, PreferEnv),
+			WithOverrideFromArgs(args, ""),
+		).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 9090, Host: "arg-host", Debug: true, Name: "from-kv"}, cfg)
+
+		cfg = Config{}
+		err = New(WithPrefix("SOURCES"), WithSources(flags, defaults), WithKVSource(kv, KVSourceOnly)).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 7070, Name: "from-kv"}, cfg)
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		var cfg Config
+		err := New(WithPrefix("SOURCES"), WithSources()).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 8080, Host: "env-host"}, cfg)
+
+		cfg = Config{}
+		err = New(WithPrefix("SOURCES"), WithSources(defaults), WithSources()).Load(&cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Port: 80, Host: "localhost", Debug: true}, cfg)
+	})
+}
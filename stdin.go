@@ -0,0 +1,43 @@
+package goconfig
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// StdinSentinel is the value that makes a field tagged stdin:"true" read its
+// value from stdin, e.g. DB_PASSWORD=-.
+const StdinSentinel = "-"
+
+// isStdinTagged reports whether the field is tagged stdin:"true".
+func (*Loader) isStdinTagged(tf reflect.StructField) bool {
+	stdin, _ := strconv.ParseBool(tf.Tag.Get("stdin"))
+	return stdin
+}
+
+// readStdin reads one line from the reader set with WithStdin, or os.Stdin,
+// without its line ending. The reader is buffered once per load so several
+// fields read consecutive lines.
+func (c *Loader) readStdin() (string, error) {
+	if c.state.stdin == nil {
+		r := c.stdin
+		if r == nil {
+			r = os.Stdin
+		}
+
+		c.state.stdin = bufio.NewReader(r)
+	}
+
+	line, err := c.state.stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", errors.Wrap(err, "no line to read")
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,48 @@
+package goconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdin(t *testing.T) {
+	type DB struct {
+		User     string
+		Password string `stdin:"true"`
+		Token    string `stdin:"true"`
+		Note     string
+	}
+
+	t.Run("sentinel reads a line", func(t *testing.T) {
+		t.Setenv("STDIN_USER", "app")
+		t.Setenv("STDIN_PASSWORD", "-")
+		t.Setenv("STDIN_TOKEN", "-")
+		t.Setenv("STDIN_NOTE", "-")
+
+		var cfg DB
+		err := New(WithPrefix("STDIN"), WithStdin(strings.NewReader("s3cret\r\nabc"))).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, DB{User: "app", Password: "s3cret", Token: "abc", Note: "-"}, cfg)
+	})
+
+	t.Run("other values are used as is", func(t *testing.T) {
+		t.Setenv("STDIN_PASSWORD", "from-env")
+
+		var cfg DB
+		err := New(WithPrefix("STDIN"), WithStdin(strings.NewReader("unused\n"))).Load(&cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, "from-env", cfg.Password)
+	})
+
+	t.Run("nothing to read", func(t *testing.T) {
+		t.Setenv("STDIN_PASSWORD", "-")
+
+		err := New(WithPrefix("STDIN"), WithStdin(strings.NewReader(""))).Load(&DB{})
+		assert.ErrorContains(t, err, "cannot read field STDIN_PASSWORD value from stdin: no line to read")
+	})
+}
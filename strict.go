@@ -0,0 +1,97 @@
+package goconfig
+
+import (
+	stderrors "errors"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownVariable is returned by Load with WithStrictPrefix for a variable
+// under the prefix that no field reads, e.g. a misspelled APP_POTR.
+var ErrUnknownVariable = errors.New("unknown variable")
+
+// markLookedUp records a key read by a field for WithStrictPrefix, whichever
+// source it is found in.
+func (c *Loader) markLookedUp(key string) {
+	if !c.strictPrefix || c.state == nil {
+		return
+	}
+
+	if c.state.lookedUp == nil {
+		c.state.lookedUp = map[string]bool{}
+	}
+
+	c.state.lookedUp[key] = true
+}
+
+// checkUnknownVariables returns an ErrUnknownVariable error for every variable
+// of the process environment under the prefix that was not looked up by a field.
+func (c *Loader) checkUnknownVariables() error {
+	if !c.strictPrefix || c.prefix == "" {
+		return nil
+	}
+
+	namespace := c.prefix + c.sep
+
+	var unknown []string
+
+	for _, kv := range os.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if !c.inNamespace(k, namespace) || c.isLookedUp(k) {
+			continue
+		}
+
+		unknown = append(unknown, k)
+	}
+
+	slices.Sort(unknown)
+
+	errs := make([]error, 0, len(unknown))
+	for _, k := range unknown {
+		errs = append(errs, errors.Wrap(ErrUnknownVariable, k))
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// inNamespace reports whether k starts with namespace, ignoring its case with
+// WithPrefixCaseFold.
+func (c *Loader) inNamespace(k, namespace string) bool {
+	if len(k) <= len(namespace) {
+		return false
+	}
+
+	if c.prefixCaseFold {
+		return strings.EqualFold(k[:len(namespace)], namespace)
+	}
+
+	return strings.HasPrefix(k, namespace)
+}
+
+// isLookedUp reports whether a field looked up k, or a key that k matches
+// through WithPrefixCaseFold or WithEnvKeyNormalizer.
+func (c *Loader) isLookedUp(k string) bool {
+	if c.state.lookedUp[k] {
+		return true
+	}
+
+	if c.prefixCaseFold && c.state.lookedUp[c.prefix+k[len(c.prefix):]] {
+		return true
+	}
+
+	if c.envKeyNormalizer == nil {
+		return false
+	}
+
+	nk := c.envKeyNormalizer(k)
+	for key := range c.state.lookedUp {
+		if c.envKeyNormalizer(key) == nk {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package goconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictPrefix(t *testing.T) {
+	type DB struct {
+		Host string
+	}
+
+	type Config struct {
+		Port  int
+		Hosts []string
+		DB    DB
+	}
+
+	t.Setenv("STRICT_PORT", "8080")
+	t.Setenv("STRICT_HOSTS_0", "a")
+	t.Setenv("STRICT_DB_HOST", "db.local")
+	t.Setenv("STRICTER_PORT", "1")
+
+	t.Run("all variables read", func(t *testing.T) {
+		var cfg Config
+
+		err := New(WithPrefix("STRICT"), WithStrictPrefix(), WithSliceSuffixes(NumericSuffixes)).Load(&cfg)
+		require.NoError(t, err)
+		assert.Equal(t, 8080, cfg.Port)
+		assert.Equal(t, []string{"a"}, cfg.Hosts)
+	})
+
+	t.Run("typos", func(t *testing.T) {
+		t.Setenv("STRICT_POTR", "9090")
+		t.Setenv("STRICT_DB_HSOT", "db.local")
+
+		err := New(WithPrefix("STRICT"), WithStrictPrefix(), WithSliceSuffixes(NumericSuffixes)).Load(&Config{})
+		require.ErrorIs(t, err, ErrUnknownVariable)
+
+		lines := strings.Split(err.Error(), "\n")
+		assert.Equal(t, []string{"STRICT_DB_HSOT: unknown variable", "STRICT_POTR: unknown variable"}, lines)
+
+		assert.NoError(t, New(WithPrefix("STRICT"), WithSliceSuffixes(NumericSuffixes)).Load(&Config{}), "not strict by default")
+	})
+
+	t.Run("case fold", func(t *testing.T) {
+		t.Setenv("strict_PORT", "8080")
+
+		err := New(WithPrefix("STRICT"), WithStrictPrefix(), WithPrefixCaseFold(), WithSliceSuffixes(NumericSuffixes)).Load(&Config{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("aggregated", func(t *testing.T) {
+		t.Setenv("STRICT_POTR", "9090")
+		t.Setenv("STRICT_PORT", "abc")
+
+		err := New(WithPrefix("STRICT"), WithStrictPrefix(), WithErrorAggregation()).Load(&Config{})
+		assert.ErrorIs(t, err, ErrLoad)
+		assert.ErrorIs(t, err, ErrUnknownVariable)
+		assert.ErrorContains(t, err, "cannot set field STRICT_PORT value")
+	})
+
+	t.Run("overridden by args", func(t *testing.T) {
+		var cfg Config
+
+		err := New(
+			WithPrefix("STRICT"),
+			WithStrictPrefix(),
+			WithSliceSuffixes(NumericSuffixes),
+			WithOverrideFromArgs([]string{"--set", "STRICT_PORT=9090"}, ""),
+		).Load(&cfg)
+		require.NoError(t, err)
+		assert.Equal(t, 9090, cfg.Port)
+	})
+
+	t.Run("preferred kv source", func(t *testing.T) {
+		src := memorySource{values: map[string]string{"STRICT_PORT": "9090", "STRICT_DB_HOST": "kv.local"}}
+
+		for _, precedence := range []Precedence{PreferKVSource, KVSourceOnly} {
+			var cfg Config
+
+			err := New(
+				WithPrefix("STRICT"),
+				WithStrictPrefix(),
+				WithSliceSuffixes(NumericSuffixes),
+				WithKVSource(src, precedence),
+			).Load(&cfg)
+			require.NoError(t, err)
+			assert.Equal(t, 9090, cfg.Port)
+		}
+	})
+}
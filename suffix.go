@@ -0,0 +1,34 @@
+package goconfig
+
+import "strconv"
+
+// SuffixSequence returns the env key suffix of the i-th element of an indexed slice.
+// It returns false when the sequence has no more suffixes.
+// It is used with WithSliceSuffixes to discover slice elements from keys such as
+// SERVER_0, SERVER_1 or SERVER_A, SERVER_B.
+type SuffixSequence func(i int) (string, bool)
+
+// NumericSuffixes is the sequence 0, 1, 2, ...
+func NumericSuffixes(i int) (string, bool) {
+	return strconv.Itoa(i), true
+}
+
+// AlphaSuffixes is the sequence A, B, ..., Z.
+func AlphaSuffixes(i int) (string, bool) {
+	if i < 0 || i >= 26 {
+		return "", false
+	}
+
+	return string(rune('A' + i)), true
+}
+
+// ListSuffixes returns a sequence made of the given suffixes, in order.
+func ListSuffixes(suffixes ...string) SuffixSequence {
+	return func(i int) (string, bool) {
+		if i < 0 || i >= len(suffixes) {
+			return "", false
+		}
+
+		return suffixes[i], true
+	}
+}
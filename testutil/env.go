@@ -0,0 +1,67 @@
+// Package testutil provides helpers for tests loading configuration from the
+// environment.
+package testutil
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// WithEnv sets the environment variables of env, calls fn and restores the
+// previous environment: variables that were set get their previous value back
+// and variables that did not exist are unset again, even when fn panics.
+// Calls can be nested, each one restoring the environment it started from.
+//
+// Example usage:
+//
+//	testutil.WithEnv(map[string]string{"APP_PORT": "8080"}, func() {
+//		var cfg Config
+//		err := goconfig.Load(&cfg)
+//		// ...
+//	})
+//
+// The environment is shared by the whole process, so WithEnv must not be used
+// in parallel tests, like testing.T.Setenv. It panics when a variable cannot
+// be set, e.g. because its key is empty or contains "=".
+func WithEnv(env map[string]string, fn func()) {
+	defer restoreEnv(snapshotEnv(env))
+
+	for key, value := range env {
+		if err := os.Setenv(key, value); err != nil {
+			panic(errors.Wrapf(err, "cannot set environment variable %q", key))
+		}
+	}
+
+	fn()
+}
+
+// prevValue is the value of a variable before WithEnv set it.
+type prevValue struct {
+	value string
+	set   bool
+}
+
+// snapshotEnv records the current values of the keys of env.
+func snapshotEnv(env map[string]string) map[string]prevValue {
+	prev := make(map[string]prevValue, len(env))
+
+	for key := range env {
+		value, set := os.LookupEnv(key)
+		prev[key] = prevValue{value: value, set: set}
+	}
+
+	return prev
+}
+
+// restoreEnv sets the recorded values back and unsets the variables that did
+// not exist.
+func restoreEnv(prev map[string]prevValue) {
+	for key, p := range prev {
+		if p.set {
+			_ = os.Setenv(key, p.value)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	}
+}
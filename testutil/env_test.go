@@ -0,0 +1,86 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnv(t *testing.T) {
+	t.Run("restores existing and absent variables", func(t *testing.T) {
+		t.Setenv("TESTUTIL_EXISTING", "before")
+		t.Setenv("TESTUTIL_EMPTY", "")
+		require.NoError(t, os.Unsetenv("TESTUTIL_ABSENT"))
+
+		var called bool
+
+		WithEnv(map[string]string{
+			"TESTUTIL_EXISTING": "during",
+			"TESTUTIL_EMPTY":    "during",
+			"TESTUTIL_ABSENT":   "during",
+		}, func() {
+			called = true
+
+			assert.Equal(t, "during", os.Getenv("TESTUTIL_EXISTING"))
+			assert.Equal(t, "during", os.Getenv("TESTUTIL_EMPTY"))
+			assert.Equal(t, "during", os.Getenv("TESTUTIL_ABSENT"))
+		})
+
+		assert.True(t, called)
+		assert.Equal(t, "before", os.Getenv("TESTUTIL_EXISTING"))
+
+		v, ok := os.LookupEnv("TESTUTIL_EMPTY")
+		assert.True(t, ok, "a set but empty variable stays set")
+		assert.Empty(t, v)
+
+		_, ok = os.LookupEnv("TESTUTIL_ABSENT")
+		assert.False(t, ok, "an absent variable is unset again")
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		t.Setenv("TESTUTIL_NESTED", "outer-before")
+
+		WithEnv(map[string]string{"TESTUTIL_NESTED": "outer", "TESTUTIL_OUTER": "outer"}, func() {
+			WithEnv(map[string]string{"TESTUTIL_NESTED": "inner", "TESTUTIL_INNER": "inner"}, func() {
+				assert.Equal(t, "inner", os.Getenv("TESTUTIL_NESTED"))
+				assert.Equal(t, "outer", os.Getenv("TESTUTIL_OUTER"))
+				assert.Equal(t, "inner", os.Getenv("TESTUTIL_INNER"))
+			})
+
+			assert.Equal(t, "outer", os.Getenv("TESTUTIL_NESTED"))
+			assert.Equal(t, "outer", os.Getenv("TESTUTIL_OUTER"))
+
+			_, ok := os.LookupEnv("TESTUTIL_INNER")
+			assert.False(t, ok)
+		})
+
+		assert.Equal(t, "outer-before", os.Getenv("TESTUTIL_NESTED"))
+
+		_, ok := os.LookupEnv("TESTUTIL_OUTER")
+		assert.False(t, ok)
+	})
+
+	t.Run("restores on panic", func(t *testing.T) {
+		t.Setenv("TESTUTIL_PANIC", "before")
+
+		assert.Panics(t, func() {
+			WithEnv(map[string]string{"TESTUTIL_PANIC": "during"}, func() {
+				panic("boom")
+			})
+		})
+
+		assert.Equal(t, "before", os.Getenv("TESTUTIL_PANIC"))
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Setenv("TESTUTIL_VALID", "before")
+
+		assert.Panics(t, func() {
+			WithEnv(map[string]string{"TESTUTIL_VALID": "during", "": "value"}, func() {})
+		})
+
+		assert.Equal(t, "before", os.Getenv("TESTUTIL_VALID"))
+	})
+}
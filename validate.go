@@ -0,0 +1,18 @@
+package goconfig
+
+import "github.com/pkg/errors"
+
+// validate runs the validators registered with WithValueValidator in order on
+// the loaded struct and returns the first error, or collects every error with
+// WithErrorAggregation.
+func (c *Loader) validate(s any) error {
+	for _, validator := range c.validators {
+		if err := validator(s); err != nil {
+			if err = errors.Wrap(err, "config validation failed"); !c.collectErr(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
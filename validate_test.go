@@ -0,0 +1,66 @@
+package goconfig
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errCertRequired = errors.New("cert path is required when TLS is enabled")
+
+func TestValueValidator(t *testing.T) {
+	type Config struct {
+		TLSEnabled bool
+		CertPath   string
+	}
+
+	var calls []string
+
+	tlsRule := func(s any) error {
+		calls = append(calls, "tls")
+
+		cfg := s.(*Config)
+		if cfg.TLSEnabled && cfg.CertPath == "" {
+			return errCertRequired
+		}
+
+		return nil
+	}
+
+	second := func(any) error {
+		calls = append(calls, "second")
+		return nil
+	}
+
+	loader := New(WithPrefix("VV"), WithValueValidator(tlsRule), WithValueValidator(second))
+
+	t.Run("valid", func(t *testing.T) {
+		calls = nil
+		t.Setenv("VV_TLS_ENABLED", "true")
+		t.Setenv("VV_CERT_PATH", "/etc/tls/cert.pem")
+
+		var cfg Config
+		require.NoError(t, loader.Load(&cfg))
+		assert.Equal(t, []string{"tls", "second"}, calls, "validators run in order")
+	})
+
+	t.Run("cross-field rule", func(t *testing.T) {
+		calls = nil
+		t.Setenv("VV_TLS_ENABLED", "true")
+
+		err := loader.Load(&Config{})
+		assert.ErrorIs(t, err, errCertRequired)
+		assert.EqualError(t, err, "config validation failed: cert path is required when TLS is enabled")
+		assert.Equal(t, []string{"tls"}, calls, "the first error stops validation")
+	})
+
+	t.Run("not called when loading fails", func(t *testing.T) {
+		calls = nil
+		t.Setenv("VV_TLS_ENABLED", "maybe")
+
+		assert.Error(t, loader.Load(&Config{}))
+		assert.Empty(t, calls)
+	})
+}